@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AliasConfig is the "aliases" section of a Config.
+type AliasConfig interface {
+	Get(name string) (string, error)
+	Set(name, expansion string) error
+	Delete(name string) error
+	All() map[string]string
+	Keys() []string
+}
+
+type aliasConfig struct {
+	node *yaml.Node
+}
+
+func (a *aliasConfig) Get(name string) (string, error) {
+	if v := findMapValue(a.node, name); v != nil {
+		return v.Value, nil
+	}
+	return "", fmt.Errorf("no such alias %s", name)
+}
+
+func (a *aliasConfig) Set(name, expansion string) error {
+	value := &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: expansion,
+		Style: styleForExpansion(expansion),
+	}
+	setMapValue(a.node, name, value)
+	return nil
+}
+
+func (a *aliasConfig) Delete(name string) error {
+	if deleteMapValue(a.node, name) {
+		return nil
+	}
+	return fmt.Errorf("no such alias %s", name)
+}
+
+func (a *aliasConfig) All() map[string]string {
+	result := make(map[string]string, len(a.node.Content)/2)
+	for i := 0; i+1 < len(a.node.Content); i += 2 {
+		result[a.node.Content[i].Value] = a.node.Content[i+1].Value
+	}
+	return result
+}
+
+// Keys returns the alias names in the order they appear in the document.
+func (a *aliasConfig) Keys() []string {
+	keys := make([]string, 0, len(a.node.Content)/2)
+	for i := 0; i+1 < len(a.node.Content); i += 2 {
+		keys = append(keys, a.node.Content[i].Value)
+	}
+	return keys
+}
+
+// SortedKeys returns the alias names sorted lexically. Import processes
+// aliases in this order so results are deterministic regardless of the
+// order keys appear in the source document.
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func styleForExpansion(expansion string) yaml.Style {
+	if strings.Contains(expansion, "\n") {
+		return yaml.LiteralStyle
+	}
+	if strings.HasPrefix(expansion, "!") || strings.ContainsAny(expansion, ":#") {
+		return yaml.SingleQuotedStyle
+	}
+	return 0
+}
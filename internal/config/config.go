@@ -0,0 +1,112 @@
+// Package config implements a minimal, round-trip preserving reader/writer
+// for gh's YAML configuration file. It is intentionally small: it only knows
+// about the parts of the config that commands in this package need (right
+// now, the aliases map), but it is built on top of yaml.Node so that
+// unrelated keys, comments, and formatting choices (like block scalars) in
+// the user's existing config survive a read-modify-write round trip.
+package config
+
+import (
+	"bytes"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mainConfigFileName = "config.yml"
+
+// writeConfigFile persists the rendered config document. Tests replace this
+// with StubWriteConfig to capture the output instead of touching disk.
+var writeConfigFile = func(filename string, data []byte) error {
+	return os.WriteFile(filename, data, 0600)
+}
+
+// Config is a handle on a loaded gh configuration document.
+type Config interface {
+	Aliases() AliasConfig
+	Write() error
+}
+
+type fileConfig struct {
+	root *yaml.Node
+}
+
+// NewBlankConfig returns an empty configuration document.
+func NewBlankConfig() Config {
+	return NewFromString("")
+}
+
+// NewFromString parses str as a gh config document, preserving its
+// structure (including block scalar style) for any values it doesn't
+// modify.
+func NewFromString(str string) Config {
+	root := &yaml.Node{}
+	_ = yaml.Unmarshal([]byte(str), root)
+	if root.Kind == 0 {
+		root.Kind = yaml.DocumentNode
+	}
+	if len(root.Content) == 0 {
+		root.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	return &fileConfig{root: root}
+}
+
+func (c *fileConfig) documentRoot() *yaml.Node {
+	return c.root.Content[0]
+}
+
+// Aliases returns the "aliases" map of the config document, creating it if
+// it does not already exist.
+func (c *fileConfig) Aliases() AliasConfig {
+	root := c.documentRoot()
+	node := findMapValue(root, "aliases")
+	if node == nil {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMapValue(root, "aliases", node)
+	}
+	return &aliasConfig{node: node}
+}
+
+// Write renders the document back to YAML and persists it.
+func (c *fileConfig) Write() error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(c.documentRoot()); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return writeConfigFile(mainConfigFileName, buf.Bytes())
+}
+
+func findMapValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMapValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	m.Content = append(m.Content, keyNode, value)
+}
+
+func deleteMapValue(m *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"io"
+	"testing"
+)
+
+// StubWriteConfig replaces the config file writer for the duration of the
+// test, capturing what would have been written instead of touching disk.
+// Call the returned function to copy the captured main config into mainBuf.
+// hostsBuf is accepted for symmetry with the real writer and is left
+// untouched, since only the main config file is captured.
+func StubWriteConfig(t *testing.T) func(mainBuf, hostsBuf io.Writer) {
+	t.Helper()
+
+	orig := writeConfigFile
+	var mainData []byte
+	writeConfigFile = func(_ string, data []byte) error {
+		mainData = data
+		return nil
+	}
+	t.Cleanup(func() { writeConfigFile = orig })
+
+	return func(mainBuf, hostsBuf io.Writer) {
+		_, _ = mainBuf.Write(mainData)
+	}
+}
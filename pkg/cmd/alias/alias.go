@@ -0,0 +1,25 @@
+package alias
+
+import (
+	cmdExport "github.com/cli/cli/v2/pkg/cmd/alias/export"
+	cmdImports "github.com/cli/cli/v2/pkg/cmd/alias/imports"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAlias(f *cmdutil.Factory) *cobra.Command {
+	aliasCmd := &cobra.Command{
+		Use:   "alias <command>",
+		Short: "Create command shortcuts",
+		Long: `Aliases can be used to make shortcuts for gh commands or to compose multiple commands.
+
+Run "gh help alias create" to learn more.`,
+	}
+
+	cmdutil.DisableAuthCheck(aliasCmd)
+
+	aliasCmd.AddCommand(cmdImports.NewCmdImports(f, nil))
+	aliasCmd.AddCommand(cmdExport.NewCmdExport(f, nil))
+
+	return aliasCmd
+}
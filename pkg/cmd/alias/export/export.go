@@ -0,0 +1,163 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type ExportOptions struct {
+	IO     *iostreams.IOStreams
+	Config func() (config.Config, error)
+
+	OutputFile string
+	Format     string
+	Names      []string
+	Shell      string
+}
+
+func NewCmdExport(f *cmdutil.Factory, runF func(*ExportOptions) error) *cobra.Command {
+	opts := &ExportOptions{
+		IO:     f.IOStreams,
+		Config: f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export aliases to a file",
+		Long: heredoc.Doc(`
+			Write the current aliases out in a format suitable for "gh alias imports",
+			so they can be shared or restored on another machine.
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Format != "yaml" && opts.Format != "json" {
+				return cmdutil.FlagErrorf("invalid format: %s", opts.Format)
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return exportRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OutputFile, "output", "o", "", "Write to `file` instead of STDOUT")
+	cmd.Flags().StringVar(&opts.Format, "format", "yaml", "Output format: {yaml|json}")
+	cmd.Flags().StringSliceVar(&opts.Names, "names", nil, "Export only the given comma-separated alias names")
+	cmd.Flags().StringVar(&opts.Shell, "shell", "", "Emit shell alias declarations instead of a config file: {bash|zsh|fish}")
+
+	return cmd
+}
+
+func exportRun(opts *ExportOptions) error {
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+	aliasCfg := cfg.Aliases()
+
+	names := opts.Names
+	if len(names) == 0 {
+		names = aliasCfg.Keys()
+	} else {
+		for _, name := range names {
+			if _, err := aliasCfg.Get(name); err != nil {
+				return fmt.Errorf("no such alias %s", name)
+			}
+		}
+	}
+
+	aliases := make(map[string]string, len(names))
+	for _, name := range names {
+		expansion, err := aliasCfg.Get(name)
+		if err != nil {
+			return err
+		}
+		aliases[name] = expansion
+	}
+
+	out := opts.IO.Out
+	if opts.OutputFile != "" {
+		f, err := os.Create(opts.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if opts.Shell != "" {
+		return writeShellAliases(out, opts.Shell, names, aliases)
+	}
+
+	switch opts.Format {
+	case "json":
+		return writeJSON(out, aliases)
+	default:
+		return writeYAML(out, names, aliases)
+	}
+}
+
+func writeJSON(w interface{ Write([]byte) (int, error) }, aliases map[string]string) error {
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func writeYAML(w interface{ Write([]byte) (int, error) }, names []string, aliases map[string]string) error {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, name := range names {
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+		valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: aliases[name]}
+		if strings.Contains(aliases[name], "\n") {
+			valueNode.Style = yaml.LiteralStyle
+		}
+		root.Content = append(root.Content, keyNode, valueNode)
+	}
+
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func writeShellAliases(w interface{ Write([]byte) (int, error) }, shell string, names []string, aliases map[string]string) error {
+	sort.Strings(names)
+	for _, name := range names {
+		line, err := shellAliasLine(shell, name, aliases[name])
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(line + "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shellAliasLine(shell, name, expansion string) (string, error) {
+	escaped := strings.ReplaceAll("gh "+expansion, "'", `'\''`)
+	switch shell {
+	case "bash", "zsh":
+		return fmt.Sprintf("alias %s='%s'", name, escaped), nil
+	case "fish":
+		return fmt.Sprintf("alias %s '%s'", name, escaped), nil
+	default:
+		return "", cmdutil.FlagErrorf("unsupported shell: %s", shell)
+	}
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasExport(t *testing.T) {
+	initialConfig := heredoc.Doc(`
+        aliases:
+            co: pr checkout
+            igrep: '!gh issue list --label="$1" | grep "$2"'
+        editor: vim
+    `)
+
+	tests := []struct {
+		name       string
+		input      string
+		wantStdout string
+		wantErr    string
+	}{
+		{
+			name:  "default yaml format",
+			input: "",
+			wantStdout: heredoc.Doc(`
+                co: pr checkout
+                igrep: '!gh issue list --label="$1" | grep "$2"'
+            `),
+		},
+		{
+			name:       "json format",
+			input:      "--format json",
+			wantStdout: "{\n  \"co\": \"pr checkout\",\n  \"igrep\": \"!gh issue list --label=\\\"$1\\\" | grep \\\"$2\\\"\"\n}\n",
+		},
+		{
+			name:       "names filter",
+			input:      "--names co",
+			wantStdout: "co: pr checkout\n",
+		},
+		{
+			name:       "shell mode",
+			input:      "--shell bash",
+			wantStdout: "alias co='gh pr checkout'\nalias igrep='gh !gh issue list --label=\"$1\" | grep \"$2\"'\n",
+		},
+		{
+			name:    "unknown name",
+			input:   "--names nope",
+			wantErr: "no such alias nope",
+		},
+		{
+			name:    "invalid format",
+			input:   "--format xml",
+			wantErr: "invalid format: xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _, stdout, _ := iostreams.Test()
+
+			cfg := config.NewFromString(initialConfig)
+
+			factory := &cmdutil.Factory{
+				IOStreams: ios,
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+			}
+
+			cmd := NewCmdExport(factory, nil)
+
+			argv, err := shlex.Split(tt.input)
+			require.NoError(t, err)
+			cmd.SetArgs(argv)
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			_, err = cmd.ExecuteC()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
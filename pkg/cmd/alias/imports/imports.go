@@ -0,0 +1,310 @@
+package imports
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type ImportOptions struct {
+	IO     *iostreams.IOStreams
+	Config func() (config.Config, error)
+
+	Filename string
+	Clobber  bool
+	DryRun   bool
+	Diff     bool
+
+	rootCmd *cobra.Command
+}
+
+func NewCmdImports(f *cmdutil.Factory, runF func(*ImportOptions) error) *cobra.Command {
+	opts := &ImportOptions{
+		IO:     f.IOStreams,
+		Config: f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "imports [<filename> | -]",
+		Short: "Import aliases from a YAML file",
+		Long: heredoc.Doc(`
+			Bulk declare aliases from a YAML file, where keys are the aliases and
+			values are the expansions, e.g.:
+
+				co: pr checkout
+				features: |-
+					issue list --label=enhancement
+
+			Use "-" to read from standard input instead of a file.
+
+			Pass --dry-run to validate the import and report what would happen
+			without writing any changes, or --diff to additionally print a diff
+			of the resulting aliases block.
+		`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("too many arguments")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if opts.IO.IsStdinTTY() {
+					return errors.New("no filename passed and nothing on STDIN")
+				}
+				opts.Filename = "-"
+			} else {
+				opts.Filename = args[0]
+			}
+
+			opts.rootCmd = cmd.Root()
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return importsRun(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Clobber, "clobber", false, "Overwrite existing aliases of the same name")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate and report the import without writing any changes")
+	cmd.Flags().BoolVar(&opts.Diff, "diff", false, "Show a diff of the aliases that would change, implies --dry-run output without writing")
+
+	return cmd
+}
+
+func importsRun(opts *ImportOptions) error {
+	var r io.Reader
+	if opts.Filename == "-" {
+		r = opts.IO.In
+		fmt.Fprintln(opts.IO.ErrOut, "Importing aliases from standard input")
+	} else {
+		f, err := os.Open(opts.Filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+		fmt.Fprintln(opts.IO.ErrOut, "Importing aliases from file")
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	aliasesToImport, err := parseAliases(content)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+	aliasCfg := cfg.Aliases()
+
+	var before string
+	if opts.Diff {
+		before, err = renderAliasesSnapshot(aliasCfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	changed := false
+	for _, name := range config.SortedKeys(aliasesToImport) {
+		expansion := aliasesToImport[name]
+
+		if isGHCommand(opts.rootCmd, name) {
+			fmt.Fprintf(opts.IO.ErrOut, "Could not import alias %s: already a gh command\n", name)
+			continue
+		}
+
+		if !isValidExpansion(opts.rootCmd, expansion) {
+			fmt.Fprintf(opts.IO.ErrOut, "Could not import alias %s: expansion does not correspond to a gh command\n", name)
+			continue
+		}
+
+		if _, err := aliasCfg.Get(name); err == nil {
+			if !opts.Clobber {
+				fmt.Fprintf(opts.IO.ErrOut, "Could not import alias %s: already taken\n", name)
+				continue
+			}
+			if err := aliasCfg.Set(name, expansion); err != nil {
+				return err
+			}
+			changed = true
+			fmt.Fprintf(opts.IO.ErrOut, "Changed alias %s\n", name)
+			continue
+		}
+
+		if err := aliasCfg.Set(name, expansion); err != nil {
+			return err
+		}
+		changed = true
+		fmt.Fprintf(opts.IO.ErrOut, "Added alias %s\n", name)
+	}
+
+	if opts.Diff {
+		after, err := renderAliasesSnapshot(aliasCfg)
+		if err != nil {
+			return err
+		}
+		printUnifiedDiff(opts.IO.Out, before, after)
+	}
+
+	if opts.DryRun || opts.Diff || !changed {
+		return nil
+	}
+
+	return cfg.Write()
+}
+
+// renderAliasesSnapshot renders the current aliases as a YAML "aliases:"
+// block, for use as one side of a --diff comparison.
+func renderAliasesSnapshot(aliasCfg config.AliasConfig) (string, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, name := range aliasCfg.Keys() {
+		expansion, err := aliasCfg.Get(name)
+		if err != nil {
+			return "", err
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+		valNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: expansion}
+		if strings.Contains(expansion, "\n") {
+			valNode.Style = yaml.LiteralStyle
+		}
+		node.Content = append(node.Content, keyNode, valNode)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	var indented strings.Builder
+	indented.WriteString("aliases:\n")
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		indented.WriteString("    " + line + "\n")
+	}
+	return indented.String(), nil
+}
+
+// printUnifiedDiff prints a minimal line-oriented diff between before and
+// after, in the style of "diff -u" but without hunk headers.
+func printUnifiedDiff(w io.Writer, before, after string) {
+	beforeLines := strings.Split(strings.TrimRight(before, "\n"), "\n")
+	afterLines := strings.Split(strings.TrimRight(after, "\n"), "\n")
+
+	for _, op := range diffLines(beforeLines, afterLines) {
+		fmt.Fprintln(w, op)
+	}
+}
+
+// diffLines computes a simple longest-common-subsequence diff between a and
+// b, returning lines prefixed with "-", "+", or " ".
+func diffLines(a, b []string) []string {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, "- "+a[i])
+	}
+	for ; j < m; j++ {
+		out = append(out, "+ "+b[j])
+	}
+	return out
+}
+
+// parseAliases decodes a YAML or JSON document into a flat alias name ->
+// expansion map. JSON is accepted because it is a subset of YAML.
+func parseAliases(content []byte) (map[string]string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases file: %w", err)
+	}
+
+	result := map[string]string{}
+	if len(root.Content) == 0 {
+		return result, nil
+	}
+
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, errors.New("aliases file must contain a mapping of alias name to expansion")
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		result[mapping.Content[i].Value] = mapping.Content[i+1].Value
+	}
+	return result, nil
+}
+
+// isGHCommand reports whether name resolves to an existing gh command.
+func isGHCommand(rootCmd *cobra.Command, name string) bool {
+	cmd, _, err := rootCmd.Find([]string{name})
+	return err == nil && cmd != rootCmd
+}
+
+// isValidExpansion reports whether expansion is either a shell alias
+// (prefixed with "!") or resolves to an existing gh command.
+func isValidExpansion(rootCmd *cobra.Command, expansion string) bool {
+	if strings.HasPrefix(strings.TrimSpace(expansion), "!") {
+		return true
+	}
+
+	split, err := shlex.Split(expansion)
+	if err != nil || len(split) == 0 {
+		return false
+	}
+
+	cmd, _, err := rootCmd.Find(split)
+	return err == nil && cmd != rootCmd
+}
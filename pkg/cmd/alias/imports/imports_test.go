@@ -224,6 +224,62 @@ func TestAliasImports(t *testing.T) {
 	}
 }
 
+func TestAliasImports_DryRunAndDiff(t *testing.T) {
+	tmpFile := filepath.Join(os.TempDir(), "test-dry-run.yml")
+	defer os.Remove(tmpFile)
+
+	tests := []struct {
+		name             string
+		input            string
+		fileContents     string
+		initialConfig    string
+		expectedErrLines []string
+		expectedOutLines []string
+	}{
+		{
+			name:  "dry-run reports without writing",
+			input: "--dry-run " + tmpFile,
+			fileContents: heredoc.Doc(`
+                co: pr checkout
+            `),
+			expectedErrLines: []string{"Importing aliases from file", "Added alias co"},
+			expectedOutLines: []string{},
+		},
+		{
+			name:  "diff prints the projected change",
+			input: "--diff " + tmpFile,
+			fileContents: heredoc.Doc(`
+                co: pr checkout
+            `),
+			expectedErrLines: []string{"Importing aliases from file", "Added alias co"},
+			expectedOutLines: []string{`\+     co: pr checkout`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(tmpFile, []byte(tt.fileContents), 0600))
+
+			readConfigs := config.StubWriteConfig(t)
+
+			cfg := config.NewFromString(tt.initialConfig)
+
+			output, err := runCommand(cfg, true, tt.input, "")
+			require.NoError(t, err)
+
+			mainBuf := bytes.Buffer{}
+			readConfigs(&mainBuf, io.Discard)
+
+			//nolint:staticcheck // prefer exact matchers over ExpectLines
+			test.ExpectLines(t, output.Stderr(), tt.expectedErrLines...)
+			//nolint:staticcheck // prefer exact matchers over ExpectLines
+			test.ExpectLines(t, output.String(), tt.expectedOutLines...)
+
+			assert.Equal(t, "", mainBuf.String(), "dry-run and diff must not write config")
+		})
+	}
+}
+
 func runCommand(cfg config.Config, isTTY bool, cli, in string) (*test.CmdOut, error) {
 	ios, stdin, stdout, stderr := iostreams.Test()
 	ios.SetStdoutTTY(isTTY)
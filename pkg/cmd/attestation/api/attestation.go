@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	GetAttestationByRepoAndSubjectDigestPath  = "repos/%s/attestations/%s"
+	GetAttestationByOwnerAndSubjectDigestPath = "orgs/%s/attestations/%s"
+)
+
+// Attestation represents a single artifact attestation returned by the
+// GitHub attestations API. Bundle holds the attestation's raw DSSE envelope,
+// which the download command writes back out verbatim as one line of its
+// JSON Lines output.
+type Attestation struct {
+	Bundle json.RawMessage `json:"bundle"`
+}
+
+// AttestationsResponse is the shape of a page of the attestations REST API.
+type AttestationsResponse struct {
+	Attestations []*Attestation `json:"attestations"`
+}
+
+// PredicateType returns the in-toto predicate type recorded in the
+// attestation's DSSE envelope, decoding the envelope's base64 payload to
+// read it.
+func (a *Attestation) PredicateType() (string, error) {
+	var envelope struct {
+		DsseEnvelope struct {
+			Payload string `json:"payload"`
+		} `json:"dsseEnvelope"`
+	}
+	if err := json.Unmarshal(a.Bundle, &envelope); err != nil {
+		return "", fmt.Errorf("failed to parse attestation bundle: %w", err)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.DsseEnvelope.Payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode attestation payload: %w", err)
+	}
+
+	var statement struct {
+		PredicateType string `json:"predicateType"`
+	}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return "", fmt.Errorf("failed to parse attestation payload: %w", err)
+	}
+
+	return statement.PredicateType, nil
+}
+
+type ErrNoAttestations struct {
+	name   string
+	digest string
+}
+
+func (e ErrNoAttestations) Error() string {
+	return fmt.Sprintf("no attestations found for digest %s in %s", e.digest, e.name)
+}
+
+func newErrNoAttestations(name, digest string) ErrNoAttestations {
+	return ErrNoAttestations{name, digest}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/logging"
+)
+
+const (
+	DefaultLimit     = 30
+	maxLimitForFlag  = 1000
+	maxLimitForFetch = 100
+)
+
+// githubApiClient makes REST calls to the GitHub API.
+type githubApiClient interface {
+	RESTWithNext(hostname, method, p string, body interface{}, data interface{}) (string, error)
+}
+
+// Client fetches artifact attestations from the GitHub attestations API.
+type Client interface {
+	GetByRepoAndDigest(repo, digest string, limit int) ([]*Attestation, error)
+	GetByOwnerAndDigest(owner, digest string, limit int) ([]*Attestation, error)
+	// StreamByRepoAndDigest is like GetByRepoAndDigest, but delivers each
+	// attestation to ch as soon as its page is fetched instead of buffering
+	// the full result set in memory, so callers can start processing
+	// attestations concurrently with the rest of the fetch.
+	StreamByRepoAndDigest(ctx context.Context, repo, digest string, limit int, ch chan<- *Attestation) error
+	// StreamByOwnerAndDigest is the StreamByRepoAndDigest counterpart of
+	// GetByOwnerAndDigest.
+	StreamByOwnerAndDigest(ctx context.Context, owner, digest string, limit int, ch chan<- *Attestation) error
+}
+
+type LiveClient struct {
+	githubAPI githubApiClient
+	host      string
+	logger    *logging.Logger
+}
+
+func NewLiveClient(hc *http.Client, logger *logging.Logger) *LiveClient {
+	return &LiveClient{
+		githubAPI: api.NewClientFromHTTP(hc),
+		logger:    logger,
+	}
+}
+
+func (c *LiveClient) buildRepoAndDigestURL(repo, digest string) string {
+	return fmt.Sprintf(GetAttestationByRepoAndSubjectDigestPath, strings.Trim(repo, "/"), digest)
+}
+
+func (c *LiveClient) buildOwnerAndDigestURL(owner, digest string) string {
+	return fmt.Sprintf(GetAttestationByOwnerAndSubjectDigestPath, strings.Trim(owner, "/"), digest)
+}
+
+// GetByRepoAndDigest fetches the attestations for an artifact digest, scoped
+// to a single repo.
+func (c *LiveClient) GetByRepoAndDigest(repo, digest string, limit int) ([]*Attestation, error) {
+	return c.getAttestations(c.buildRepoAndDigestURL(repo, digest), repo, digest, limit)
+}
+
+// GetByOwnerAndDigest fetches the attestations for an artifact digest, scoped
+// to every repo owned by owner.
+func (c *LiveClient) GetByOwnerAndDigest(owner, digest string, limit int) ([]*Attestation, error) {
+	return c.getAttestations(c.buildOwnerAndDigestURL(owner, digest), owner, digest, limit)
+}
+
+// StreamByRepoAndDigest streams the attestations for an artifact digest,
+// scoped to a single repo.
+func (c *LiveClient) StreamByRepoAndDigest(ctx context.Context, repo, digest string, limit int, ch chan<- *Attestation) error {
+	return c.streamAttestations(ctx, c.buildRepoAndDigestURL(repo, digest), repo, digest, limit, ch)
+}
+
+// StreamByOwnerAndDigest streams the attestations for an artifact digest,
+// scoped to every repo owned by owner.
+func (c *LiveClient) StreamByOwnerAndDigest(ctx context.Context, owner, digest string, limit int, ch chan<- *Attestation) error {
+	return c.streamAttestations(ctx, c.buildOwnerAndDigestURL(owner, digest), owner, digest, limit, ch)
+}
+
+func (c *LiveClient) getAttestations(url, name, digest string, limit int) ([]*Attestation, error) {
+	var attestations []*Attestation
+	err := c.fetchPages(url, digest, limit, func(page []*Attestation) error {
+		attestations = append(attestations, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(attestations) == 0 {
+		return nil, newErrNoAttestations(name, digest)
+	}
+	return attestations, nil
+}
+
+func (c *LiveClient) streamAttestations(ctx context.Context, url, name, digest string, limit int, ch chan<- *Attestation) error {
+	found := 0
+	err := c.fetchPages(url, digest, limit, func(page []*Attestation) error {
+		for _, att := range page {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ch <- att:
+				found++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return newErrNoAttestations(name, digest)
+	}
+	return nil
+}
+
+// fetchPages pages through url, calling onPage with each page of
+// attestations fetched, until limit attestations have been seen or the
+// GitHub API reports there are no more pages.
+func (c *LiveClient) fetchPages(url, digest string, limit int, onPage func([]*Attestation) error) error {
+	c.logger.VerbosePrintf("Fetching attestations for artifact digest %s\n\n", digest)
+
+	perPage := limit
+	if perPage <= 0 || perPage > maxLimitForFlag {
+		return fmt.Errorf("limit must be greater than 0 and less than or equal to %d", maxLimitForFlag)
+	}
+	if perPage > maxLimitForFetch {
+		perPage = maxLimitForFetch
+	}
+
+	url = fmt.Sprintf("%s?per_page=%d", url, perPage)
+
+	seen := 0
+	for url != "" && seen < limit {
+		var resp AttestationsResponse
+		nextURL, err := c.githubAPI.RESTWithNext("", http.MethodGet, url, nil, &resp)
+		if err != nil {
+			return err
+		}
+		url = nextURL
+
+		page := resp.Attestations
+		if seen+len(page) > limit {
+			page = page[:limit-seen]
+		}
+		seen += len(page)
+
+		if err := onPage(page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+type MockClient struct {
+	OnGetByRepoAndDigest     func(repo, digest string, limit int) ([]*Attestation, error)
+	OnGetByOwnerAndDigest    func(owner, digest string, limit int) ([]*Attestation, error)
+	OnStreamByRepoAndDigest  func(ctx context.Context, repo, digest string, limit int, ch chan<- *Attestation) error
+	OnStreamByOwnerAndDigest func(ctx context.Context, owner, digest string, limit int, ch chan<- *Attestation) error
+}
+
+func (m MockClient) GetByRepoAndDigest(repo, digest string, limit int) ([]*Attestation, error) {
+	return m.OnGetByRepoAndDigest(repo, digest, limit)
+}
+
+func (m MockClient) GetByOwnerAndDigest(owner, digest string, limit int) ([]*Attestation, error) {
+	return m.OnGetByOwnerAndDigest(owner, digest, limit)
+}
+
+func (m MockClient) StreamByRepoAndDigest(ctx context.Context, repo, digest string, limit int, ch chan<- *Attestation) error {
+	return m.OnStreamByRepoAndDigest(ctx, repo, digest, limit, ch)
+}
+
+func (m MockClient) StreamByOwnerAndDigest(ctx context.Context, owner, digest string, limit int, ch chan<- *Attestation) error {
+	return m.OnStreamByOwnerAndDigest(ctx, owner, digest, limit, ch)
+}
+
+func makeTestAttestation() Attestation {
+	bundle, _ := json.Marshal(map[string]interface{}{
+		"dsseEnvelope": map[string]string{
+			"payload": base64.StdEncoding.EncodeToString([]byte(`{"predicateType":"https://slsa.dev/provenance/v1"}`)),
+		},
+	})
+	return Attestation{Bundle: bundle}
+}
+
+func onGetByRepoAndDigestSuccess(repo, digest string, limit int) ([]*Attestation, error) {
+	att1, att2 := makeTestAttestation(), makeTestAttestation()
+	return []*Attestation{&att1, &att2}, nil
+}
+
+func onGetByOwnerAndDigestSuccess(owner, digest string, limit int) ([]*Attestation, error) {
+	att1, att2 := makeTestAttestation(), makeTestAttestation()
+	return []*Attestation{&att1, &att2}, nil
+}
+
+func onStreamByRepoAndDigestSuccess(ctx context.Context, repo, digest string, limit int, ch chan<- *Attestation) error {
+	atts, _ := onGetByRepoAndDigestSuccess(repo, digest, limit)
+	for _, att := range atts {
+		ch <- att
+	}
+	return nil
+}
+
+func onStreamByOwnerAndDigestSuccess(ctx context.Context, owner, digest string, limit int, ch chan<- *Attestation) error {
+	atts, _ := onGetByOwnerAndDigestSuccess(owner, digest, limit)
+	for _, att := range atts {
+		ch <- att
+	}
+	return nil
+}
+
+// NewTestClient returns a MockClient preconfigured to succeed with a couple
+// of placeholder attestations, suitable as a default Options.APIClient in
+// tests that don't care about the attestation contents.
+func NewTestClient() *MockClient {
+	return &MockClient{
+		OnGetByRepoAndDigest:     onGetByRepoAndDigestSuccess,
+		OnGetByOwnerAndDigest:    onGetByOwnerAndDigestSuccess,
+		OnStreamByRepoAndDigest:  onStreamByRepoAndDigestSuccess,
+		OnStreamByOwnerAndDigest: onStreamByOwnerAndDigestSuccess,
+	}
+}
+
+// NewTestAttestationWithPredicateType returns a test Attestation whose DSSE
+// envelope declares the given in-toto predicate type, for exercising
+// predicate-type filtering.
+func NewTestAttestationWithPredicateType(t *testing.T, predicateType string) *Attestation {
+	t.Helper()
+
+	payload, err := json.Marshal(map[string]string{"predicateType": predicateType})
+	if err != nil {
+		t.Fatalf("failed to marshal test attestation payload: %v", err)
+	}
+
+	bundle, err := json.Marshal(map[string]interface{}{
+		"dsseEnvelope": map[string]string{
+			"payload": base64.StdEncoding.EncodeToString(payload),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal test attestation bundle: %v", err)
+	}
+
+	return &Attestation{Bundle: bundle}
+}
@@ -0,0 +1,442 @@
+package download
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/logging"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLimit           = 30
+	defaultDigestAlgorithm = "sha256"
+	defaultConcurrency     = 4
+)
+
+// Options captures the inputs to the attestation download command.
+type Options struct {
+	ArtifactPath    string
+	DigestAlgorithm string
+	Owner           string
+	Repo            string
+	Limit           int
+	OutputPath      string
+	PredicateTypes  []string
+	Concurrency     int
+	Resume          bool
+
+	APIClient api.Client
+	OCIClient oci.Client
+	Logger    *logging.Logger
+	Context   context.Context
+}
+
+func NewDownloadCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command {
+	opts := &Options{Limit: defaultLimit, DigestAlgorithm: defaultDigestAlgorithm, Concurrency: defaultConcurrency}
+
+	cmd := &cobra.Command{
+		Use:   "download [<file-path> | oci://<image-uri>] [--owner | --repo] [--predicate-type]",
+		Short: "Download attestations for an artifact as a JSON Lines file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ArtifactPath = args[0]
+			opts.Context = cmd.Context()
+
+			if opts.Owner == "" && opts.Repo == "" {
+				return cmdutil.FlagErrorf("must specify one of --owner or --repo")
+			}
+			if opts.Owner != "" && opts.Repo != "" {
+				return cmdutil.FlagErrorf("only one of --owner or --repo may be specified")
+			}
+
+			switch opts.DigestAlgorithm {
+			case "sha256", "sha512":
+			default:
+				return cmdutil.FlagErrorf("unsupported digest algorithm: %s", opts.DigestAlgorithm)
+			}
+
+			if opts.Limit < 1 {
+				return cmdutil.FlagErrorf("invalid limit: %d", opts.Limit)
+			}
+
+			if opts.Concurrency < 1 {
+				return cmdutil.FlagErrorf("invalid concurrency: %d", opts.Concurrency)
+			}
+
+			if opts.OutputPath == "" {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				opts.OutputPath = wd
+			}
+
+			if opts.Logger == nil {
+				opts.Logger = logging.NewLogger(cmd.ErrOrStderr(), false, false)
+			}
+			if opts.APIClient == nil {
+				hc, err := f.HttpClient()
+				if err != nil {
+					return err
+				}
+				opts.APIClient = api.NewLiveClient(hc, opts.Logger)
+			}
+			if opts.OCIClient == nil {
+				opts.OCIClient = oci.NewLiveClient()
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runDownload(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.DigestAlgorithm, "digest-alg", defaultDigestAlgorithm, "The algorithm used to compute a digest of the artifact: {sha256|sha512}")
+	cmd.Flags().StringVar(&opts.Owner, "owner", "", "GitHub organization to scope attestation lookup to")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository name in the format <owner>/<repo>")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", defaultLimit, "Maximum number of attestations to fetch")
+	cmd.Flags().StringVarP(&opts.OutputPath, "output-path", "o", "", "Path to output the JSON Lines file to")
+	cmd.Flags().StringArrayVar(&opts.PredicateTypes, "predicate-type", nil, "Only download attestations matching the given in-toto predicate type, can be specified multiple times")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", defaultConcurrency, "Number of attestations to fetch and write concurrently")
+	cmd.Flags().BoolVar(&opts.Resume, "resume", false, "Resume a previously interrupted download, skipping envelopes already present in the output file")
+
+	return cmd
+}
+
+func runDownload(opts *Options) error {
+	if opts.OCIClient == nil {
+		return fmt.Errorf("an OCI client must be provided")
+	}
+	if opts.APIClient == nil {
+		return fmt.Errorf("an API client must be provided")
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+
+	a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+	if err != nil {
+		return fmt.Errorf("failed to digest artifact: %w", err)
+	}
+
+	filePath := createJSONLinesFilePath(a.DigestWithAlg(), opts.OutputPath)
+	idxPath := indexFilePath(filePath)
+
+	seen := make(map[string]bool)
+	if opts.Resume {
+		seen, err = loadOrRebuildIndex(idxPath, filePath)
+		if err != nil {
+			return err
+		}
+	} else {
+		// A fresh (non-resumed) download always starts from an empty file.
+		_ = os.Remove(filePath)
+		_ = os.Remove(idxPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	w, err := newLineWriter(filePath, idxPath, opts.Resume)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	ch := make(chan *api.Attestation)
+	streamErrCh := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		if opts.Owner != "" {
+			streamErrCh <- opts.APIClient.StreamByOwnerAndDigest(ctx, opts.Owner, a.DigestWithAlg(), opts.Limit, ch)
+		} else {
+			streamErrCh <- opts.APIClient.StreamByRepoAndDigest(ctx, opts.Repo, a.DigestWithAlg(), opts.Limit, ch)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var writeErrOnce sync.Once
+	var writeErr error
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for att := range ch {
+				if !matchesPredicateType(att, opts.PredicateTypes) {
+					continue
+				}
+				if err := w.writeAttestation(att, seen); err != nil {
+					writeErrOnce.Do(func() { writeErr = err })
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := <-streamErrCh; err != nil {
+		_ = w.close()
+		return err
+	}
+	if writeErr != nil {
+		_ = w.close()
+		return writeErr
+	}
+
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	if w.written == 0 {
+		if !fileHasContent(filePath) {
+			_ = os.Remove(filePath)
+			_ = os.Remove(idxPath)
+			opts.Logger.Println("no attestations found")
+		}
+	}
+
+	return nil
+}
+
+// matchesPredicateType reports whether att should be kept, given the
+// (possibly empty) set of requested predicate types.
+func matchesPredicateType(att *api.Attestation, predicateTypes []string) bool {
+	if len(predicateTypes) == 0 {
+		return true
+	}
+
+	wanted := make(map[string]bool, len(predicateTypes))
+	for _, pt := range predicateTypes {
+		wanted[normalizePredicateType(pt)] = true
+	}
+
+	pt, err := att.PredicateType()
+	if err != nil {
+		return false
+	}
+	return wanted[normalizePredicateType(pt)]
+}
+
+// normalizePredicateType allows users to pass short aliases (sbom,
+// provenance) as well as full in-toto predicate type URIs.
+func normalizePredicateType(pt string) string {
+	switch pt {
+	case "provenance":
+		return "https://slsa.dev/provenance/v1"
+	case "sbom":
+		return "https://spdx.dev/Document"
+	case "cyclonedx":
+		return "https://cyclonedx.org/bom"
+	default:
+		return pt
+	}
+}
+
+func createJSONLinesFilePath(digestWithAlg, outputPath string) string {
+	fileName := fmt.Sprintf("%s.jsonl", digestWithAlg)
+	if outputPath == "" {
+		return fileName
+	}
+	return path.Join(outputPath, fileName)
+}
+
+func indexFilePath(jsonLinesPath string) string {
+	return jsonLinesPath + ".idx"
+}
+
+func fileHasContent(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Size() > 0
+}
+
+// lineWriter serializes concurrent writes from the download worker pool into
+// the destination .jsonl file and its companion .idx sidecar, so that every
+// worker can write without the caller needing its own locking.
+type lineWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	idxFile *os.File
+	out     *bufio.Writer
+	offset  int64
+	written int
+}
+
+func newLineWriter(filePath, idxPath string, resume bool) (*lineWriter, error) {
+	flags := os.O_CREATE | os.O_WRONLY
+	offset := int64(0)
+	if resume {
+		flags |= os.O_APPEND
+		if info, err := os.Stat(filePath); err == nil {
+			offset = info.Size()
+		}
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(filePath, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	idxFlags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !resume {
+		idxFlags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	idxFile, err := os.OpenFile(idxPath, idxFlags, 0644)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &lineWriter{file: f, idxFile: idxFile, out: bufio.NewWriter(f), offset: offset}, nil
+}
+
+// writeAttestation hashes att's envelope, skips it if the hash is already in
+// seen (already present from a prior run), and otherwise appends it to the
+// .jsonl file and records its offset in the .idx sidecar.
+func (w *lineWriter) writeAttestation(att *api.Attestation, seen map[string]bool) error {
+	data, err := json.Marshal(att)
+	if err != nil {
+		return err
+	}
+	hash := envelopeHash(data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if seen[hash] {
+		return nil
+	}
+
+	offset := w.offset
+	line := append(data, '\n')
+	n, err := w.out.Write(line)
+	if err != nil {
+		return err
+	}
+	w.offset += int64(n)
+	w.written++
+
+	if _, err := fmt.Fprintf(w.idxFile, "%s %d\n", hash, offset); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *lineWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.out.Flush(); err != nil {
+		w.file.Close()
+		w.idxFile.Close()
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		w.idxFile.Close()
+		return err
+	}
+	return w.idxFile.Close()
+}
+
+func envelopeHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadOrRebuildIndex reads the envelope-hash -> offset sidecar for a resumed
+// download. If the sidecar is missing or malformed, it is rebuilt by
+// rescanning the existing .jsonl file, so a corrupt or partially-written
+// index never blocks a resume.
+func loadOrRebuildIndex(idxPath, jsonLinesPath string) (map[string]bool, error) {
+	seen, err := readIndex(idxPath)
+	if err == nil {
+		return seen, nil
+	}
+	return rebuildIndexFromJSONLines(jsonLinesPath, idxPath)
+}
+
+func readIndex(idxPath string) (map[string]bool, error) {
+	f, err := os.Open(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("corrupt index entry: %q", line)
+		}
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("corrupt index entry: %q", line)
+		}
+		seen[fields[0]] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return seen, nil
+}
+
+// rebuildIndexFromJSONLines recomputes the envelope hash of every line
+// already present in jsonLinesPath and rewrites idxPath from scratch.
+func rebuildIndexFromJSONLines(jsonLinesPath, idxPath string) (map[string]bool, error) {
+	f, err := os.Open(jsonLinesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]bool{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	idxFile, err := os.OpenFile(idxPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer idxFile.Close()
+
+	seen := make(map[string]bool)
+	var offset int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		hash := envelopeHash(line)
+		seen[hash] = true
+		if _, err := fmt.Fprintf(idxFile, "%s %d\n", hash, offset); err != nil {
+			return nil, err
+		}
+		offset += int64(len(line)) + 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return seen, nil
+}
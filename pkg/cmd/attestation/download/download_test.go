@@ -3,6 +3,8 @@ package download
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -171,6 +173,36 @@ func TestNewDownloadCmd(t *testing.T) {
 	}
 }
 
+func TestNewDownloadCmd_PredicateTypeFlag(t *testing.T) {
+	testIO, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: testIO,
+		HttpClient: func() (*http.Client, error) {
+			reg := &httpmock.Registry{}
+			client := &http.Client{}
+			httpmock.ReplaceTripper(client, reg)
+			return client, nil
+		},
+	}
+
+	var opts *Options
+	cmd := NewDownloadCmd(f, func(o *Options) error {
+		opts = o
+		return nil
+	})
+
+	argv, err := shlex.Split("../test/data/sigstore-js-2.1.0.tgz --owner sigstore --predicate-type sbom --predicate-type provenance")
+	assert.NoError(t, err)
+	cmd.SetArgs(argv)
+	cmd.SetIn(&bytes.Buffer{})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	_, err = cmd.ExecuteC()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"sbom", "provenance"}, opts.PredicateTypes)
+}
+
 func TestRunDownload(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -251,8 +283,8 @@ func TestRunDownload(t *testing.T) {
 	t.Run("no attestations found", func(t *testing.T) {
 		opts := baseOpts
 		opts.APIClient = api.MockClient{
-			OnGetByOwnerAndDigest: func(repo, digest string, limit int) ([]*api.Attestation, error) {
-				return nil, nil
+			OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+				return nil
 			},
 		}
 
@@ -264,6 +296,46 @@ func TestRunDownload(t *testing.T) {
 		require.NoFileExists(t, artifact.DigestWithAlg())
 	})
 
+	t.Run("narrows results to the requested predicate types", func(t *testing.T) {
+		opts := baseOpts
+		opts.PredicateTypes = []string{"sbom"}
+		opts.APIClient = api.MockClient{
+			OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+				ch <- api.NewTestAttestationWithPredicateType(t, "https://spdx.dev/Document")
+				ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+				return nil
+			},
+		}
+
+		err := runDownload(&opts)
+		require.NoError(t, err)
+
+		a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+		require.NoError(t, err)
+
+		actualLineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+		require.NoError(t, err)
+		require.Equal(t, 1, actualLineCount)
+	})
+
+	t.Run("empty result when no attestation matches the predicate type filter", func(t *testing.T) {
+		opts := baseOpts
+		opts.PredicateTypes = []string{"cyclonedx"}
+		opts.APIClient = api.MockClient{
+			OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+				ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+				return nil
+			},
+		}
+
+		err := runDownload(&opts)
+		require.NoError(t, err)
+
+		a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+		require.NoError(t, err)
+		require.NoFileExists(t, fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	})
+
 	t.Run("cannot download OCI artifact", func(t *testing.T) {
 		opts := baseOpts
 		opts.ArtifactPath = "oci://ghcr.io/github/test"
@@ -288,6 +360,163 @@ func TestRunDownload(t *testing.T) {
 	})
 }
 
+func TestRunDownload_ParallelConcurrency(t *testing.T) {
+	tempDir := t.TempDir()
+	opts := Options{
+		ArtifactPath:    "../test/data/sigstore-js-2.1.0.tgz",
+		APIClient:       api.NewTestClient(),
+		OCIClient:       oci.MockClient{},
+		DigestAlgorithm: "sha512",
+		Owner:           "sigstore",
+		OutputPath:      tempDir,
+		Limit:           30,
+		Concurrency:     8,
+		Logger:          logging.NewTestLogger(),
+	}
+
+	err := runDownload(&opts)
+	require.NoError(t, err)
+
+	a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+	require.NoError(t, err)
+
+	actualLineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	require.NoError(t, err)
+	require.Equal(t, 2, actualLineCount)
+}
+
+func TestRunDownload_Resume(t *testing.T) {
+	tempDir := t.TempDir()
+	baseOpts := Options{
+		ArtifactPath:    "../test/data/sigstore-js-2.1.0.tgz",
+		OCIClient:       oci.MockClient{},
+		DigestAlgorithm: "sha512",
+		Owner:           "sigstore",
+		OutputPath:      tempDir,
+		Limit:           30,
+		Logger:          logging.NewTestLogger(),
+	}
+
+	interrupted := errors.New("network interrupted")
+	firstOpts := baseOpts
+	firstOpts.APIClient = api.MockClient{
+		OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://spdx.dev/Document")
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+			return interrupted
+		},
+	}
+
+	err := runDownload(&firstOpts)
+	require.ErrorIs(t, err, interrupted)
+
+	a, err := artifact.NewDigestedArtifact(firstOpts.OCIClient, firstOpts.ArtifactPath, firstOpts.DigestAlgorithm)
+	require.NoError(t, err)
+
+	partialLineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	require.NoError(t, err)
+	require.Equal(t, 2, partialLineCount)
+
+	resumeOpts := baseOpts
+	resumeOpts.Resume = true
+	resumeOpts.APIClient = api.MockClient{
+		OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://spdx.dev/Document")
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://cyclonedx.org/bom")
+			return nil
+		},
+	}
+
+	err = runDownload(&resumeOpts)
+	require.NoError(t, err)
+
+	finalLineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	require.NoError(t, err)
+	require.Equal(t, 3, finalLineCount, "resume must not duplicate already-downloaded envelopes")
+}
+
+func TestRunDownload_ResumeRebuildsCorruptIndex(t *testing.T) {
+	tempDir := t.TempDir()
+	opts := Options{
+		ArtifactPath:    "../test/data/sigstore-js-2.1.0.tgz",
+		OCIClient:       oci.MockClient{},
+		DigestAlgorithm: "sha512",
+		Owner:           "sigstore",
+		OutputPath:      tempDir,
+		Limit:           30,
+		Logger:          logging.NewTestLogger(),
+		APIClient: api.MockClient{
+			OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+				ch <- api.NewTestAttestationWithPredicateType(t, "https://spdx.dev/Document")
+				ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+				return nil
+			},
+		},
+	}
+
+	err := runDownload(&opts)
+	require.NoError(t, err)
+
+	a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+	require.NoError(t, err)
+
+	idxPath := fmt.Sprintf("%s/%s.jsonl.idx", tempDir, a.DigestWithAlg())
+	require.NoError(t, os.WriteFile(idxPath, []byte("not a valid index\n"), 0600))
+
+	resumeOpts := opts
+	resumeOpts.Resume = true
+	resumeOpts.APIClient = api.MockClient{
+		OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://spdx.dev/Document")
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://slsa.dev/provenance/v1")
+			ch <- api.NewTestAttestationWithPredicateType(t, "https://cyclonedx.org/bom")
+			return nil
+		},
+	}
+
+	err = runDownload(&resumeOpts)
+	require.NoError(t, err)
+
+	lineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	require.NoError(t, err)
+	require.Equal(t, 3, lineCount, "a corrupt index must be rebuilt from the existing .jsonl rather than blocking resume")
+}
+
+func TestRunDownload_Stress(t *testing.T) {
+	tempDir := t.TempDir()
+	const total = 500
+
+	opts := Options{
+		ArtifactPath:    "../test/data/sigstore-js-2.1.0.tgz",
+		OCIClient:       oci.MockClient{},
+		DigestAlgorithm: "sha512",
+		Owner:           "sigstore",
+		OutputPath:      tempDir,
+		Limit:           total,
+		Concurrency:     16,
+		Logger:          logging.NewTestLogger(),
+		APIClient: api.MockClient{
+			OnStreamByOwnerAndDigest: func(ctx context.Context, owner, digest string, limit int, ch chan<- *api.Attestation) error {
+				for i := 0; i < total; i++ {
+					ch <- api.NewTestAttestationWithPredicateType(t, fmt.Sprintf("https://example.com/predicate/%d", i))
+				}
+				return nil
+			},
+		},
+	}
+
+	err := runDownload(&opts)
+	require.NoError(t, err)
+
+	a, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+	require.NoError(t, err)
+
+	lineCount, err := countLines(fmt.Sprintf("%s/%s.jsonl", tempDir, a.DigestWithAlg()))
+	require.NoError(t, err)
+	require.Equal(t, total, lineCount)
+}
+
 func TestCreateJSONLinesFilePath(t *testing.T) {
 	tempDir := t.TempDir()
 	artifact, err := artifact.NewDigestedArtifact(oci.MockClient{}, "../test/data/sigstore-js-2.1.0.tgz", "sha512")
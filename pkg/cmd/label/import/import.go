@@ -0,0 +1,176 @@
+package importcmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/label/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type ImportOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	BaseRepo   func() (ghrepo.Interface, error)
+
+	FileName string
+	Clobber  bool
+	Prune    bool
+}
+
+// importLabel is the shape of a single entry in the imported file. NewName
+// allows an existing label to be renamed as part of the import.
+type importLabel struct {
+	Name        string `yaml:"name" json:"name"`
+	Color       string `yaml:"color" json:"color"`
+	Description string `yaml:"description" json:"description"`
+	NewName     string `yaml:"new_name" json:"new_name"`
+}
+
+func NewCmdImport(f *cmdutil.Factory, runF func(*ImportOptions) error) *cobra.Command {
+	opts := &ImportOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create or update labels from a file",
+		Long: heredoc.Doc(`
+			Create or update labels in bulk from a file.
+
+			The file must be a YAML or JSON document listing labels, each with a
+			name, color, and optional description. A label may also carry a
+			new_name field, which renames an existing label matched by name.
+
+			Pass "-" to read the file from STDIN.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.FileName = args[0]
+			opts.BaseRepo = f.BaseRepo
+
+			if runF != nil {
+				return runF(opts)
+			}
+
+			return importRun(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Clobber, "clobber", false, "Overwrite color and description for labels that already exist")
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Delete remote labels that are not present in the input file")
+
+	return cmd
+}
+
+func importRun(opts *ImportOptions) error {
+	var r io.Reader
+	if opts.FileName == "-" {
+		r = opts.IO.In
+		defer opts.IO.In.Close()
+	} else {
+		f, err := os.Open(opts.FileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var labels []importLabel
+	if err := yaml.Unmarshal(content, &labels); err != nil {
+		return fmt.Errorf("failed to parse labels file: %w", err)
+	}
+
+	baseRepo, err := opts.BaseRepo()
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return err
+	}
+
+	if opts.FileName == "-" {
+		fmt.Fprintln(opts.IO.ErrOut, "Importing labels from standard input")
+	} else {
+		fmt.Fprintln(opts.IO.ErrOut, "Importing labels from file")
+	}
+
+	repositoryID, err := shared.RepositoryID(httpClient, baseRepo)
+	if err != nil {
+		return err
+	}
+
+	existing, err := shared.ListLabels(httpClient, baseRepo, shared.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]shared.Label, len(existing.Labels))
+	for _, l := range existing.Labels {
+		byName[l.Name] = l
+	}
+
+	seen := make(map[string]bool, len(labels))
+	for _, input := range labels {
+		seen[input.Name] = true
+
+		if cur, ok := byName[input.Name]; ok {
+			if !opts.Clobber {
+				fmt.Fprintf(opts.IO.ErrOut, "Could not import label %s: already taken\n", input.Name)
+				continue
+			}
+			err := shared.UpdateLabel(httpClient, baseRepo, cur.ID, input.NewName, shared.Label{
+				Color:       input.Color,
+				Description: input.Description,
+			})
+			if err != nil {
+				fmt.Fprintf(opts.IO.ErrOut, "Could not import label %s: %s\n", input.Name, err)
+				continue
+			}
+			fmt.Fprintf(opts.IO.ErrOut, "Changed label %s\n", input.Name)
+			continue
+		}
+
+		err := shared.CreateLabel(httpClient, baseRepo, repositoryID, shared.Label{
+			Name:        input.Name,
+			Color:       input.Color,
+			Description: input.Description,
+		})
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "Could not import label %s: %s\n", input.Name, err)
+			continue
+		}
+		fmt.Fprintf(opts.IO.ErrOut, "Added label %s\n", input.Name)
+	}
+
+	if opts.Prune {
+		for _, l := range existing.Labels {
+			if seen[l.Name] {
+				continue
+			}
+			if err := shared.DeleteLabel(httpClient, baseRepo, l.ID); err != nil {
+				fmt.Fprintf(opts.IO.ErrOut, "Could not prune label %s: %s\n", l.Name, err)
+				continue
+			}
+			fmt.Fprintf(opts.IO.ErrOut, "Deleted label %s\n", l.Name)
+		}
+	}
+
+	return nil
+}
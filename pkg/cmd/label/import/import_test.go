@@ -0,0 +1,151 @@
+package importcmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/httpmock"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/cli/cli/v2/test"
+	"github.com/google/shlex"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelImport(t *testing.T) {
+	tmpFile := filepath.Join(os.TempDir(), "labels.yml")
+	defer os.Remove(tmpFile)
+
+	tests := []struct {
+		name             string
+		input            string
+		fileContents     string
+		httpStubs        func(*httpmock.Registry)
+		expectedErrLines []string
+	}{
+		{
+			name:  "adds new labels",
+			input: tmpFile,
+			fileContents: heredoc.Doc(`
+                - name: bug
+                  color: d73a4a
+                  description: Something isn't working
+            `),
+			httpStubs: func(reg *httpmock.Registry) {
+				reg.Register(
+					httpmock.GraphQL(`query LabelRepositoryID\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"id":"REPOID"}}}`))
+				reg.Register(
+					httpmock.GraphQL(`query LabelList\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"labels":{"totalCount":0,"nodes":[],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}}`))
+				reg.Register(
+					httpmock.GraphQL(`mutation CreateLabel\b`),
+					httpmock.StringResponse(`{"data":{"createLabel":{"label":{"id":"1","name":"bug","color":"d73a4a","description":"Something isn't working"}}}}`))
+			},
+			expectedErrLines: []string{"Importing labels from file", "Added label bug"},
+		},
+		{
+			name:  "existing label without clobber is skipped",
+			input: tmpFile,
+			fileContents: heredoc.Doc(`
+                - name: bug
+                  color: d73a4a
+            `),
+			httpStubs: func(reg *httpmock.Registry) {
+				reg.Register(
+					httpmock.GraphQL(`query LabelRepositoryID\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"id":"REPOID"}}}`))
+				reg.Register(
+					httpmock.GraphQL(`query LabelList\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"labels":{"totalCount":1,"nodes":[{"name":"bug","color":"000000","description":""}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}}`))
+			},
+			expectedErrLines: []string{"Importing labels from file", "Could not import label bug: already taken"},
+		},
+		{
+			name:  "existing label with clobber is updated",
+			input: "--clobber " + tmpFile,
+			fileContents: heredoc.Doc(`
+                - name: bug
+                  color: d73a4a
+            `),
+			httpStubs: func(reg *httpmock.Registry) {
+				reg.Register(
+					httpmock.GraphQL(`query LabelRepositoryID\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"id":"REPOID"}}}`))
+				reg.Register(
+					httpmock.GraphQL(`query LabelList\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"labels":{"totalCount":1,"nodes":[{"name":"bug","color":"000000","description":""}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}}`))
+				reg.Register(
+					httpmock.GraphQL(`mutation UpdateLabel\b`),
+					httpmock.StringResponse(`{"data":{"updateLabel":{"label":{"id":"1","name":"bug","color":"d73a4a","description":""}}}}`))
+			},
+			expectedErrLines: []string{"Importing labels from file", "Changed label bug"},
+		},
+		{
+			name:  "prune deletes labels absent from input",
+			input: "--prune " + tmpFile,
+			fileContents: heredoc.Doc(`
+                - name: bug
+                  color: d73a4a
+            `),
+			httpStubs: func(reg *httpmock.Registry) {
+				reg.Register(
+					httpmock.GraphQL(`query LabelRepositoryID\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"id":"REPOID"}}}`))
+				reg.Register(
+					httpmock.GraphQL(`query LabelList\b`),
+					httpmock.StringResponse(`{"data":{"repository":{"labels":{"totalCount":2,"nodes":[{"id":"1","name":"bug","color":"d73a4a","description":""},{"id":"2","name":"stale","color":"ffffff","description":""}],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}}`))
+				reg.Register(
+					httpmock.GraphQL(`mutation DeleteLabel\b`),
+					httpmock.StringResponse(`{"data":{"deleteLabel":{"clientMutationId":""}}}`))
+			},
+			expectedErrLines: []string{"Importing labels from file", "Could not import label bug: already taken", "Deleted label stale"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(tmpFile, []byte(tt.fileContents), 0600))
+
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			tt.httpStubs(reg)
+
+			ios, _, _, stderr := iostreams.Test()
+			ios.SetStdoutTTY(true)
+			ios.SetStdinTTY(true)
+			ios.SetStderrTTY(true)
+
+			factory := &cmdutil.Factory{
+				IOStreams: ios,
+				HttpClient: func() (*http.Client, error) {
+					return &http.Client{Transport: reg}, nil
+				},
+				BaseRepo: func() (ghrepo.Interface, error) {
+					return ghrepo.New("OWNER", "REPO"), nil
+				},
+			}
+
+			cmd := NewCmdImport(factory, nil)
+
+			argv, err := shlex.Split(tt.input)
+			require.NoError(t, err)
+			cmd.SetArgs(argv)
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			_, err = cmd.ExecuteC()
+			require.NoError(t, err)
+
+			//nolint:staticcheck // prefer exact matchers over ExpectLines
+			test.ExpectLines(t, stderr.String(), tt.expectedErrLines...)
+		})
+	}
+}
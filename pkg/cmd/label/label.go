@@ -0,0 +1,23 @@
+package label
+
+import (
+	cmdImport "github.com/cli/cli/v2/pkg/cmd/label/import"
+	cmdList "github.com/cli/cli/v2/pkg/cmd/label/list"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdLabel(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label <command>",
+		Short: "Manage labels",
+		Long:  "Work with GitHub labels.",
+	}
+
+	cmdutil.EnableRepoOverride(cmd, f)
+
+	cmd.AddCommand(cmdList.NewCmdList(f, nil))
+	cmd.AddCommand(cmdImport.NewCmdImport(f, nil))
+
+	return cmd
+}
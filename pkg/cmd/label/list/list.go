@@ -0,0 +1,133 @@
+package list
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/internal/tableprinter"
+	"github.com/cli/cli/v2/internal/text"
+	"github.com/cli/cli/v2/pkg/cmd/label/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type ListOptions struct {
+	HttpClient func() (*http.Client, error)
+	IO         *iostreams.IOStreams
+	BaseRepo   func() (ghrepo.Interface, error)
+	Browser    cmdutil.Browser
+	Exporter   cmdutil.Exporter
+
+	WebMode bool
+	Limit   int
+	Search  string
+	Sort    string
+	Order   string
+}
+
+func NewCmdList(f *cmdutil.Factory, runF func(*ListOptions) error) *cobra.Command {
+	opts := &ListOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+		Browser:    f.Browser,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List labels in a repository",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.BaseRepo = f.BaseRepo
+
+			if opts.Limit < 1 {
+				return cmdutil.FlagErrorf("invalid limit: %v", opts.Limit)
+			}
+
+			if opts.Sort != "" && opts.Sort != "name" && opts.Sort != "created" {
+				return cmdutil.FlagErrorf("invalid sort: %s", opts.Sort)
+			}
+			if opts.Order != "" && opts.Order != "asc" && opts.Order != "desc" {
+				return cmdutil.FlagErrorf("invalid order: %s", opts.Order)
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+
+			return listRun(opts)
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "L", 30, "Maximum number of labels to fetch")
+	cmd.Flags().BoolVarP(&opts.WebMode, "web", "w", false, "Open the list of labels in the web browser")
+	cmd.Flags().StringVarP(&opts.Search, "search", "S", "", "Search label names and descriptions")
+	cmd.Flags().StringVar(&opts.Sort, "sort", "name", "Sort fetched labels: {name|created}")
+	cmd.Flags().StringVar(&opts.Order, "order", "asc", "Order of labels returned: {asc|desc}")
+	cmdutil.AddJSONFlags(cmd, &opts.Exporter, shared.LabelFields)
+
+	return cmd
+}
+
+func listRun(opts *ListOptions) error {
+	baseRepo, err := opts.BaseRepo()
+	if err != nil {
+		return err
+	}
+
+	if opts.WebMode {
+		labelsURL := ghrepo.GenerateRepoURL(baseRepo, "labels")
+
+		if opts.IO.IsStdoutTTY() {
+			fmt.Fprintf(opts.IO.ErrOut, "Opening %s in your browser.\n", text.DisplayURL(labelsURL))
+		}
+
+		return opts.Browser.Browse(labelsURL)
+	}
+
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return err
+	}
+
+	listResult, err := shared.ListLabels(httpClient, baseRepo, shared.ListOptions{
+		Limit: opts.Limit,
+		Query: opts.Search,
+		Sort:  opts.Sort,
+		Order: opts.Order,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Exporter != nil {
+		return opts.Exporter.Write(opts.IO, listResult.Labels)
+	}
+
+	if err := opts.IO.StartPager(); err == nil {
+		defer opts.IO.StopPager()
+	}
+
+	if opts.IO.IsStdoutTTY() {
+		title := listHeader(baseRepo, len(listResult.Labels), listResult.TotalCount)
+		fmt.Fprintf(opts.IO.Out, "\n%s\n\n", title)
+	}
+
+	tp := tableprinter.New(opts.IO, tableprinter.NoHeader)
+	for _, label := range listResult.Labels {
+		tp.AddField(label.Name)
+		tp.AddField(label.Description)
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+func listHeader(repo ghrepo.Interface, labelsCount, totalCount int) string {
+	if totalCount == 0 {
+		return fmt.Sprintf("There are no labels in %s", ghrepo.FullName(repo))
+	}
+
+	return fmt.Sprintf("Showing %d of %s in %s", labelsCount, text.Pluralize(totalCount, "label"), ghrepo.FullName(repo))
+}
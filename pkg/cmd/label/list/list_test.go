@@ -221,3 +221,91 @@ func TestLabelList_web(t *testing.T) {
 	assert.Equal(t, "Opening github.com/OWNER/REPO/labels in your browser.\n", output.ErrBuf.String())
 	browser.Verify(t, "https://github.com/OWNER/REPO/labels")
 }
+
+func TestLabelList_withJSON(t *testing.T) {
+	http := &httpmock.Registry{}
+	defer http.Verify(t)
+
+	http.Register(
+		httpmock.GraphQL(`query LabelList\b`),
+		httpmock.StringResponse(`
+		{
+			"data": {
+				"repository": {
+					"labels": {
+						"totalCount": 1,
+						"nodes": [
+							{
+								"name": "bug",
+								"color": "d73a4a",
+								"description": "This is a bug label",
+								"createdAt": "2020-01-01T00:00:00Z",
+								"url": "https://github.com/OWNER/REPO/labels/bug"
+							}
+						],
+						"pageInfo": {
+							"hasNextPage": false,
+							"endCursor": null
+						}
+					}
+				}
+			}
+		}`),
+	)
+
+	output, _, err := runCommand(http, false, "--json name,color,description,createdAt,url")
+	if err != nil {
+		t.Errorf("error running command `label list`: %v", err)
+	}
+
+	assert.JSONEq(t, `[{"name":"bug","color":"d73a4a","description":"This is a bug label","createdAt":"2020-01-01T00:00:00Z","url":"https://github.com/OWNER/REPO/labels/bug"}]`, output.String())
+}
+
+func TestLabelList_withSearchAndSort(t *testing.T) {
+	http := &httpmock.Registry{}
+	defer http.Verify(t)
+
+	http.Register(
+		httpmock.GraphQL(`query LabelList\b`),
+		httpmock.GraphQLQuery(`
+		{
+			"data": {
+				"repository": {
+					"labels": {
+						"totalCount": 1,
+						"nodes": [
+							{
+								"name": "bug",
+								"color": "d73a4a",
+								"description": "This is a bug label"
+							}
+						],
+						"pageInfo": {
+							"hasNextPage": false,
+							"endCursor": null
+						}
+					}
+				}
+			}
+		}`, func(_ string, inputs map[string]interface{}) {
+			assert.Equal(t, "bug", inputs["query"])
+			order := inputs["order"].(map[string]interface{})
+			assert.Equal(t, "CREATED_AT", order["field"])
+			assert.Equal(t, "DESC", order["direction"])
+		}),
+	)
+
+	_, _, err := runCommand(http, false, "--search bug --sort created --order desc")
+	if err != nil {
+		t.Errorf("error running command `label list`: %v", err)
+	}
+}
+
+func TestLabelList_withInvalidSortFlag(t *testing.T) {
+	http := &httpmock.Registry{}
+	defer http.Verify(t)
+
+	_, _, err := runCommand(http, false, "--sort nonsense")
+
+	assert.EqualError(t, err, "invalid sort: nonsense")
+}
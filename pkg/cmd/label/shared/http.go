@@ -0,0 +1,271 @@
+package shared
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+)
+
+type Label struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+	URL         string `json:"url"`
+}
+
+// ExportData implements cmdutil.Exporter so Label can be rendered by
+// --json/--jq/--template.
+func (l Label) ExportData(fields []string) map[string]interface{} {
+	data := map[string]interface{}{}
+	for _, f := range fields {
+		switch f {
+		case "name":
+			data[f] = l.Name
+		case "color":
+			data[f] = l.Color
+		case "description":
+			data[f] = l.Description
+		case "createdAt":
+			data[f] = l.CreatedAt
+		case "url":
+			data[f] = l.URL
+		}
+	}
+	return data
+}
+
+// LabelFields lists the fields available to --json for label list.
+var LabelFields = []string{"name", "color", "description", "createdAt", "url"}
+
+type ListOptions struct {
+	Limit int
+	Query string
+	Sort  string
+	Order string
+}
+
+type ListResult struct {
+	Labels     []Label
+	TotalCount int
+}
+
+const listLabelsQuery = `
+query LabelList($owner: String!, $repo: String!, $query: String, $order: LabelOrder, $endCursor: String, $limit: Int!) {
+	repository(owner: $owner, name: $repo) {
+		labels(query: $query, first: $limit, after: $endCursor, orderBy: $order) {
+			totalCount
+			nodes {
+				name
+				color
+				description
+				createdAt
+				url
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+		}
+	}
+}
+`
+
+// ListLabels fetches labels for the given repository, paginating until
+// opts.Limit labels have been collected or the repository runs out of labels.
+func ListLabels(httpClient *http.Client, repo ghrepo.Interface, opts ListOptions) (*ListResult, error) {
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	pageLimit := opts.Limit
+	if pageLimit <= 0 || pageLimit > 100 {
+		pageLimit = 30
+	}
+
+	variables := map[string]interface{}{
+		"owner": repo.RepoOwner(),
+		"repo":  repo.RepoName(),
+		"limit": pageLimit,
+	}
+	if opts.Query != "" {
+		variables["query"] = opts.Query
+	}
+	if opts.Sort != "" {
+		variables["order"] = map[string]string{
+			"field":     labelOrderField(opts.Sort),
+			"direction": labelOrderDirection(opts.Order),
+		}
+	}
+
+	result := ListResult{}
+	for {
+		var response struct {
+			Repository struct {
+				Labels struct {
+					TotalCount int
+					Nodes      []Label
+					PageInfo   struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}
+
+		err := apiClient.GraphQL(repo.RepoHost(), listLabelsQuery, variables, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching labels: %w", err)
+		}
+
+		result.TotalCount = response.Repository.Labels.TotalCount
+		result.Labels = append(result.Labels, response.Repository.Labels.Nodes...)
+
+		if opts.Limit != 0 && len(result.Labels) >= opts.Limit {
+			result.Labels = result.Labels[:opts.Limit]
+			break
+		}
+
+		if !response.Repository.Labels.PageInfo.HasNextPage {
+			break
+		}
+		variables["endCursor"] = response.Repository.Labels.PageInfo.EndCursor
+	}
+
+	return &result, nil
+}
+
+func labelOrderField(sort string) string {
+	if sort == "created" {
+		return "CREATED_AT"
+	}
+	return "NAME"
+}
+
+func labelOrderDirection(order string) string {
+	if order == "desc" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+const repositoryIDQuery = `
+query LabelRepositoryID($owner: String!, $repo: String!) {
+	repository(owner: $owner, name: $repo) {
+		id
+	}
+}
+`
+
+func RepositoryID(httpClient *http.Client, repo ghrepo.Interface) (string, error) {
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	var response struct {
+		Repository struct {
+			ID string
+		}
+	}
+	variables := map[string]interface{}{
+		"owner": repo.RepoOwner(),
+		"repo":  repo.RepoName(),
+	}
+	if err := apiClient.GraphQL(repo.RepoHost(), repositoryIDQuery, variables, &response); err != nil {
+		return "", fmt.Errorf("error fetching repository: %w", err)
+	}
+	return response.Repository.ID, nil
+}
+
+const createLabelMutation = `
+mutation CreateLabel($input: CreateLabelInput!) {
+	createLabel(input: $input) {
+		label {
+			id
+			name
+			color
+			description
+		}
+	}
+}
+`
+
+// CreateLabel creates a new label on the repository identified by repositoryID.
+func CreateLabel(httpClient *http.Client, repo ghrepo.Interface, repositoryID string, label Label) error {
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"repositoryId": repositoryID,
+			"name":         label.Name,
+			"color":        label.Color,
+			"description":  label.Description,
+		},
+	}
+
+	var response struct {
+		CreateLabel struct {
+			Label Label
+		}
+	}
+	return apiClient.GraphQL(repo.RepoHost(), createLabelMutation, variables, &response)
+}
+
+const updateLabelMutation = `
+mutation UpdateLabel($input: UpdateLabelInput!) {
+	updateLabel(input: $input) {
+		label {
+			id
+			name
+			color
+			description
+		}
+	}
+}
+`
+
+// UpdateLabel updates an existing label, identified by its node ID, optionally renaming it.
+func UpdateLabel(httpClient *http.Client, repo ghrepo.Interface, labelID string, newName string, label Label) error {
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	input := map[string]interface{}{
+		"id":          labelID,
+		"color":       label.Color,
+		"description": label.Description,
+	}
+	if newName != "" {
+		input["name"] = newName
+	}
+
+	variables := map[string]interface{}{"input": input}
+
+	var response struct {
+		UpdateLabel struct {
+			Label Label
+		}
+	}
+	return apiClient.GraphQL(repo.RepoHost(), updateLabelMutation, variables, &response)
+}
+
+const deleteLabelMutation = `
+mutation DeleteLabel($input: DeleteLabelInput!) {
+	deleteLabel(input: $input) {
+		clientMutationId
+	}
+}
+`
+
+// DeleteLabel deletes the label identified by its node ID.
+func DeleteLabel(httpClient *http.Client, repo ghrepo.Interface, labelID string) error {
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{"id": labelID},
+	}
+
+	var response struct {
+		DeleteLabel struct {
+			ClientMutationID string `json:"clientMutationId"`
+		}
+	}
+	return apiClient.GraphQL(repo.RepoHost(), deleteLabelMutation, variables, &response)
+}
@@ -0,0 +1,621 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/git"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	shared "github.com/cli/cli/v2/pkg/cmd/pr/shared"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWatchInterval = 60 * time.Second
+	minWatchBackoff      = 2 * time.Second
+	maxWatchBackoff      = 60 * time.Second
+	defaultConcurrency   = 4
+	searchResultLimit    = 100
+)
+
+// sleep waits out d, returning early with ctx.Err() if ctx is canceled first.
+// It is a seam for tests to avoid actually waiting out --interval/backoff.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type UpdateOptions struct {
+	HttpClient func() (*http.Client, error)
+	GitClient  *git.Client
+	IO         *iostreams.IOStreams
+	Finder     shared.PRFinder
+	BaseRepo   func() (ghrepo.Interface, error)
+	Context    context.Context
+
+	// SelectorArg holds the first positional selector and is kept for
+	// backwards compatibility with the single-PR path; Selectors holds every
+	// selector when more than one was given.
+	SelectorArg string
+	Selectors   []string
+
+	Search      string
+	AllAuthored bool
+	Concurrency int
+
+	Rebase bool
+
+	Watch       bool
+	Interval    time.Duration
+	MaxAttempts int
+
+	// Checkout fetches the PR's head branch into the local checkout after a
+	// successful update; CheckoutPull additionally fast-forwards it.
+	Checkout     bool
+	CheckoutPull bool
+}
+
+func NewCmdUpdate(f *cmdutil.Factory, runF func(*UpdateOptions) error) *cobra.Command {
+	opts := &UpdateOptions{
+		IO:         f.IOStreams,
+		HttpClient: f.HttpClient,
+		GitClient:  f.GitClient,
+		BaseRepo:   f.BaseRepo,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "update-branch [<number> | <url> | <branch>] ...",
+		Short: "Update a pull request branch",
+		Long: heredoc.Doc(`
+			Update a pull request branch with the latest changes of its base branch.
+
+			Without an argument, the pull request that belongs to the current branch
+			is selected. Multiple selectors may be given to update several pull
+			requests in one invocation, and --search or --all-authored can be used
+			instead of selectors to update the matching pull requests (at most the
+			first 100 search results).
+
+			With --watch, the command keeps checking the pull request's base branch
+			comparison and re-issues the update until the branch is up-to-date, the
+			pull request is closed or merged, or --max-attempts is reached. --watch
+			is only supported when updating a single pull request.
+
+			With --checkout, if the local working tree is on the PR's head branch
+			and has no uncommitted changes, the freshly-updated branch is fetched
+			so the local checkout does not silently diverge from it. --checkout-pull
+			additionally fast-forwards (or rebases, if --rebase was used) the local
+			branch onto the fetched commit.
+		`),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Finder = shared.NewFinder(f)
+			opts.Context = cmd.Context()
+			opts.Selectors = args
+
+			if len(args) > 0 {
+				opts.SelectorArg = args[0]
+			}
+
+			if opts.CheckoutPull {
+				opts.Checkout = true
+			}
+
+			if opts.Search != "" && opts.AllAuthored {
+				return cmdutil.FlagErrorf("specify only one of `--search` or `--all-authored`")
+			}
+			if (opts.Search != "" || opts.AllAuthored) && len(args) > 0 {
+				return cmdutil.FlagErrorf("cannot specify pull request selectors together with `--search` or `--all-authored`")
+			}
+			if opts.Watch && (len(args) > 1 || opts.Search != "" || opts.AllAuthored) {
+				return cmdutil.FlagErrorf("`--watch` can only be used when updating a single pull request")
+			}
+			if opts.Checkout && (len(args) > 1 || opts.Search != "" || opts.AllAuthored) {
+				return cmdutil.FlagErrorf("`--checkout` can only be used when updating a single pull request")
+			}
+
+			if repoOverride, _ := cmd.Flags().GetString("repo"); repoOverride != "" && opts.SelectorArg == "" && opts.Search == "" && !opts.AllAuthored {
+				return cmdutil.FlagErrorf("argument required when using the --repo flag")
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return updateRun(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Rebase, "rebase", false, "Update PR branch by rebasing onto the latest commit on the base branch")
+	cmd.Flags().BoolVar(&opts.Watch, "watch", false, "Keep updating the PR branch until it is up-to-date")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", defaultWatchInterval, "Time to wait between update attempts when using --watch")
+	cmd.Flags().IntVar(&opts.MaxAttempts, "max-attempts", 0, "Maximum number of attempts when using --watch (default: unlimited)")
+	cmd.Flags().StringVar(&opts.Search, "search", "", "Update the pull requests matching a search query (first 100 results)")
+	cmd.Flags().BoolVar(&opts.AllAuthored, "all-authored", false, "Update the open pull requests you authored in this repository (first 100 results)")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", defaultConcurrency, "Number of pull requests to update concurrently")
+	cmd.Flags().BoolVar(&opts.Checkout, "checkout", false, "Fetch the updated branch into the local checkout, if it is currently checked out")
+	cmd.Flags().BoolVar(&opts.CheckoutPull, "checkout-pull", false, "Like --checkout, and also fast-forward (or rebase) the local branch")
+
+	return cmd
+}
+
+var prFields = []string{"id", "number", "headRefOid", "headRefName", "headRepositoryOwner", "headRepository"}
+
+func updateRun(opts *UpdateOptions) error {
+	updateMethod := "MERGE"
+	if opts.Rebase {
+		updateMethod = "REBASE"
+	}
+
+	if opts.Search != "" || opts.AllAuthored {
+		httpClient, err := opts.HttpClient()
+		if err != nil {
+			return err
+		}
+		apiClient := api.NewClientFromHTTP(httpClient)
+
+		baseRepo, err := opts.BaseRepo()
+		if err != nil {
+			return err
+		}
+
+		query := strings.TrimSpace(opts.Search)
+		if opts.AllAuthored {
+			query = strings.TrimSpace("is:open author:@me " + query)
+		}
+
+		prs, err := searchPullRequests(apiClient, baseRepo, query)
+		if err != nil {
+			return err
+		}
+		if len(prs) == 0 {
+			fmt.Fprintln(opts.IO.ErrOut, "no pull requests matched the given search")
+			return nil
+		}
+
+		return updateMany(opts, apiClient, baseRepo, prs, updateMethod)
+	}
+
+	selectors := opts.Selectors
+	if len(selectors) == 0 {
+		selectors = []string{opts.SelectorArg}
+	}
+
+	if len(selectors) == 1 {
+		pr, baseRepo, err := opts.Finder.Find(shared.FindOptions{Selector: selectors[0], Fields: prFields})
+		if err != nil {
+			return err
+		}
+
+		httpClient, err := opts.HttpClient()
+		if err != nil {
+			return err
+		}
+		apiClient := api.NewClientFromHTTP(httpClient)
+
+		headRef := pr.HeadRefName
+		if pr.HeadRepositoryOwner.Login != baseRepo.RepoOwner() {
+			headRef = fmt.Sprintf("%s:%s", pr.HeadRepositoryOwner.Login, pr.HeadRefName)
+		}
+
+		if opts.Watch {
+			return watchUntilUpToDate(opts, apiClient, baseRepo, pr, headRef, updateMethod)
+		}
+
+		return updateOnce(opts, apiClient, baseRepo, pr, headRef, updateMethod)
+	}
+
+	var prs []*api.PullRequest
+	var baseRepo ghrepo.Interface
+	for _, selector := range selectors {
+		pr, repo, err := opts.Finder.Find(shared.FindOptions{Selector: selector, Fields: prFields})
+		if err != nil {
+			return err
+		}
+		prs = append(prs, pr)
+		baseRepo = repo
+	}
+
+	httpClient, err := opts.HttpClient()
+	if err != nil {
+		return err
+	}
+	apiClient := api.NewClientFromHTTP(httpClient)
+
+	return updateMany(opts, apiClient, baseRepo, prs, updateMethod)
+}
+
+// updateOnce performs a single compare-then-update pass, the behavior of
+// `gh pr update-branch` without --watch.
+func updateOnce(opts *UpdateOptions, apiClient *api.Client, baseRepo ghrepo.Interface, pr *api.PullRequest, headRef, updateMethod string) error {
+	cs := opts.IO.ColorScheme()
+
+	compare, err := comparePullRequestBaseBranchWith(apiClient, baseRepo, pr.Number, headRef)
+	if err != nil {
+		return err
+	}
+
+	if compare.Repository.PullRequest.BaseRef.Compare.BehindBy == 0 {
+		fmt.Fprintf(opts.IO.ErrOut, "%s PR branch already up-to-date\n", cs.SuccessIcon())
+		return nil
+	}
+
+	if err := pullRequestUpdateBranch(apiClient, baseRepo, pr.ID, pr.HeadRefOid, updateMethod); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.IO.ErrOut, "%s PR branch updated\n", cs.SuccessIcon())
+	syncLocalCheckout(opts, baseRepo, pr, updateMethod)
+	return nil
+}
+
+// updateOne compares a single PR's base branch and updates it if behind,
+// returning "up-to-date" or "updated" to describe what happened. It is the
+// per-PR unit of work driven concurrently by updateMany.
+func updateOne(apiClient *api.Client, baseRepo ghrepo.Interface, pr *api.PullRequest, updateMethod string) (string, error) {
+	headRef := pr.HeadRefName
+	if pr.HeadRepositoryOwner.Login != baseRepo.RepoOwner() {
+		headRef = fmt.Sprintf("%s:%s", pr.HeadRepositoryOwner.Login, pr.HeadRefName)
+	}
+
+	compare, err := comparePullRequestBaseBranchWith(apiClient, baseRepo, pr.Number, headRef)
+	if err != nil {
+		return "", err
+	}
+
+	if compare.Repository.PullRequest.BaseRef.Compare.BehindBy == 0 {
+		return "up-to-date", nil
+	}
+
+	if err := pullRequestUpdateBranch(apiClient, baseRepo, pr.ID, pr.HeadRefOid, updateMethod); err != nil {
+		return "", err
+	}
+
+	return "updated", nil
+}
+
+// updateMany fans updateOne out across a bounded worker pool, then prints one
+// status line per PR in the order the PRs were given. A failing PR does not
+// stop the others from being attempted, but causes updateMany to return an
+// error once every PR has been processed.
+func updateMany(opts *UpdateOptions, apiClient *api.Client, baseRepo ghrepo.Interface, prs []*api.PullRequest, updateMethod string) error {
+	cs := opts.IO.ColorScheme()
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+
+	statuses := make([]string, len(prs))
+	errs := make([]error, len(prs))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, pr := range prs {
+		i, pr := i, pr
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			statuses[i], errs[i] = updateOne(apiClient, baseRepo, pr, updateMethod)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, pr := range prs {
+		switch {
+		case errs[i] != nil:
+			failed++
+			fmt.Fprintf(opts.IO.ErrOut, "%s #%d %s\n", cs.FailureIcon(), pr.Number, errs[i])
+		case statuses[i] == "up-to-date":
+			fmt.Fprintf(opts.IO.ErrOut, "- #%d already up-to-date\n", pr.Number)
+		default:
+			fmt.Fprintf(opts.IO.ErrOut, "%s #%d updated\n", cs.SuccessIcon(), pr.Number)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d pull requests failed to update", failed, len(prs))
+	}
+	return nil
+}
+
+// watchUntilUpToDate repeatedly compares the PR's base branch and re-issues
+// the update-branch mutation whenever the branch falls behind, until the
+// branch is up-to-date, the pull request is closed or merged, the context is
+// canceled, or MaxAttempts is reached. Transient errors from either GraphQL
+// call are logged and retried with capped exponential backoff rather than
+// aborting the loop.
+func watchUntilUpToDate(opts *UpdateOptions, apiClient *api.Client, baseRepo ghrepo.Interface, pr *api.PullRequest, headRef, updateMethod string) error {
+	cs := opts.IO.ColorScheme()
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	backoff := minWatchBackoff
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.MaxAttempts > 0 && attempt > opts.MaxAttempts {
+			return fmt.Errorf("gave up waiting for pull request #%d to become up-to-date after %d attempts", pr.Number, opts.MaxAttempts)
+		}
+
+		compare, err := comparePullRequestBaseBranchWith(apiClient, baseRepo, pr.Number, headRef)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s attempt %d: %s; retrying in %s\n", cs.WarningIcon(), attempt, err, backoff)
+			if err := sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff = nextWatchBackoff(backoff)
+			continue
+		}
+		backoff = minWatchBackoff
+
+		switch compare.Repository.PullRequest.State {
+		case "MERGED":
+			fmt.Fprintf(opts.IO.ErrOut, "%s pull request #%d was merged\n", cs.SuccessIcon(), pr.Number)
+			return nil
+		case "CLOSED":
+			fmt.Fprintf(opts.IO.ErrOut, "%s pull request #%d was closed\n", cs.SuccessIcon(), pr.Number)
+			return nil
+		}
+
+		if compare.Repository.PullRequest.BaseRef.Compare.BehindBy == 0 {
+			fmt.Fprintf(opts.IO.ErrOut, "%s PR branch is up-to-date\n", cs.SuccessIcon())
+			return nil
+		}
+
+		if err := pullRequestUpdateBranch(apiClient, baseRepo, pr.ID, pr.HeadRefOid, updateMethod); err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s attempt %d: %s; retrying in %s\n", cs.WarningIcon(), attempt, err, backoff)
+			if err := sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff = nextWatchBackoff(backoff)
+			continue
+		}
+
+		fmt.Fprintf(opts.IO.ErrOut, "%s PR branch updated, waiting to confirm\n", cs.SuccessIcon())
+		syncLocalCheckout(opts, baseRepo, pr, updateMethod)
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// syncLocalCheckout fetches a PR's freshly-updated head branch into the local
+// checkout when --checkout or --checkout-pull was given. It is a best-effort,
+// opt-in convenience: a working tree that isn't on the PR branch, or that has
+// uncommitted changes, is left untouched with a warning rather than failing
+// the command.
+func syncLocalCheckout(opts *UpdateOptions, repo ghrepo.Interface, pr *api.PullRequest, updateMethod string) {
+	if !opts.Checkout {
+		return
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cs := opts.IO.ColorScheme()
+
+	current, err := opts.GitClient.CurrentBranch(ctx)
+	if err != nil || current != pr.HeadRefName {
+		fmt.Fprintf(opts.IO.ErrOut, "%s not currently on branch %q; skipping local checkout\n", cs.WarningIcon(), pr.HeadRefName)
+		return
+	}
+
+	dirty, err := opts.GitClient.UncommittedChangeCount(ctx)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s could not determine working tree status; skipping local checkout: %s\n", cs.WarningIcon(), err)
+		return
+	}
+	if dirty > 0 {
+		fmt.Fprintf(opts.IO.ErrOut, "%s local working tree has uncommitted changes; skipping local checkout\n", cs.WarningIcon())
+		return
+	}
+
+	remote := checkoutRemoteName(ctx, opts.GitClient, repo)
+	if err := opts.GitClient.Fetch(ctx, remote, pr.HeadRefName); err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s failed to fetch %s: %s\n", cs.FailureIcon(), pr.HeadRefName, err)
+		return
+	}
+
+	if !opts.CheckoutPull {
+		return
+	}
+
+	syncArgs := []string{"merge", "--ff-only", "FETCH_HEAD"}
+	if updateMethod == "REBASE" {
+		syncArgs = []string{"rebase", "FETCH_HEAD"}
+	}
+
+	syncCmd, err := opts.GitClient.Command(ctx, syncArgs...)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s failed to update local branch %q: %s\n", cs.FailureIcon(), pr.HeadRefName, err)
+		return
+	}
+	if err := syncCmd.Run(); err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s failed to update local branch %q: %s\n", cs.FailureIcon(), pr.HeadRefName, err)
+		return
+	}
+
+	fmt.Fprintf(opts.IO.ErrOut, "%s updated local branch %q\n", cs.SuccessIcon(), pr.HeadRefName)
+}
+
+// checkoutRemoteName looks up the git remote that tracks repo, falling back
+// to "origin" if none is configured or the lookup fails.
+func checkoutRemoteName(ctx context.Context, gitClient *git.Client, repo ghrepo.Interface) string {
+	remotes, err := gitClient.Remotes(ctx)
+	if err != nil {
+		return "origin"
+	}
+	for _, remote := range remotes {
+		if remote.FetchURL == nil {
+			continue
+		}
+		if remoteRepo, err := ghrepo.FromURL(remote.FetchURL); err == nil &&
+			strings.EqualFold(remoteRepo.RepoOwner(), repo.RepoOwner()) &&
+			strings.EqualFold(remoteRepo.RepoName(), repo.RepoName()) {
+			return remote.Name
+		}
+	}
+	return "origin"
+}
+
+func nextWatchBackoff(d time.Duration) time.Duration {
+	d *= 2
+	if d > maxWatchBackoff {
+		return maxWatchBackoff
+	}
+	return d
+}
+
+type compareResponse struct {
+	Repository struct {
+		PullRequest struct {
+			State   string
+			BaseRef struct {
+				Compare struct {
+					AheadBy  int
+					BehindBy int
+					Status   string
+				}
+			}
+		}
+	}
+}
+
+func comparePullRequestBaseBranchWith(client *api.Client, repo ghrepo.Interface, prNumber int, headRef string) (*compareResponse, error) {
+	query := `
+	query ComparePullRequestBaseBranchWith($owner: String!, $repo: String!, $pullRequestNumber: Int!, $headRef: String!) {
+		repository(owner: $owner, name: $repo) {
+			pullRequest(number: $pullRequestNumber) {
+				state
+				baseRef {
+					compare(headRef: $headRef) {
+						aheadBy
+						behindBy
+						Status: status
+					}
+				}
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"owner":             repo.RepoOwner(),
+		"repo":              repo.RepoName(),
+		"pullRequestNumber": prNumber,
+		"headRef":           headRef,
+	}
+
+	var resp compareResponse
+	err := client.GraphQL(repo.RepoHost(), query, variables, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func pullRequestUpdateBranch(client *api.Client, repo ghrepo.Interface, prID, expectedHeadOid, updateMethod string) error {
+	mutation := `
+	mutation PullRequestUpdateBranch($input: UpdatePullRequestBranchInput!) {
+		updatePullRequestBranch(input: $input) {
+			pullRequest {
+				id
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"pullRequestId":   prID,
+			"expectedHeadOid": expectedHeadOid,
+			"updateMethod":    updateMethod,
+		},
+	}
+
+	var resp struct{}
+	return client.GraphQL(repo.RepoHost(), mutation, variables, &resp)
+}
+
+type searchResponse struct {
+	Search struct {
+		Nodes []struct {
+			ID                  string
+			Number              int
+			HeadRefOid          string
+			HeadRefName         string
+			HeadRepositoryOwner struct {
+				Login string
+			}
+		}
+	}
+}
+
+// searchPullRequests runs query scoped to repo and returns the matching pull
+// requests, for use by --search and --all-authored. It fetches a single page
+// of at most searchResultLimit results; further matches are not fetched.
+func searchPullRequests(client *api.Client, repo ghrepo.Interface, query string) ([]*api.PullRequest, error) {
+	q := `
+	query PullRequestSearch($q: String!, $limit: Int!) {
+		search(query: $q, type: ISSUE, first: $limit) {
+			nodes {
+				... on PullRequest {
+					id
+					number
+					headRefOid
+					headRefName
+					headRepositoryOwner {
+						login
+					}
+				}
+			}
+		}
+	}`
+
+	variables := map[string]interface{}{
+		"q":     fmt.Sprintf("repo:%s/%s %s", repo.RepoOwner(), repo.RepoName(), query),
+		"limit": searchResultLimit,
+	}
+
+	var resp searchResponse
+	if err := client.GraphQL(repo.RepoHost(), q, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	prs := make([]*api.PullRequest, 0, len(resp.Search.Nodes))
+	for _, n := range resp.Search.Nodes {
+		prs = append(prs, &api.PullRequest{
+			ID:                  n.ID,
+			Number:              n.Number,
+			HeadRefOid:          n.HeadRefOid,
+			HeadRefName:         n.HeadRefName,
+			HeadRepositoryOwner: api.Owner{Login: n.HeadRepositoryOwner.Login},
+		})
+	}
+	return prs, nil
+}
@@ -2,12 +2,16 @@ package update
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/cli/cli/v2/api"
 	"github.com/cli/cli/v2/git"
 	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/internal/run"
 	shared "github.com/cli/cli/v2/pkg/cmd/pr/shared"
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/cli/cli/v2/pkg/httpmock"
@@ -386,3 +390,550 @@ func Test_updateRun(t *testing.T) {
 		})
 	}
 }
+
+func Test_updateRun_checkout(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              *UpdateOptions
+		runStubs           func(*run.CommandStubber)
+		wantStderrContains []string
+	}{
+		{
+			name: "fetches and fast-forwards when on the PR branch with a clean tree",
+			input: &UpdateOptions{
+				SelectorArg:  "123",
+				Checkout:     true,
+				CheckoutPull: true,
+			},
+			runStubs: func(cs *run.CommandStubber) {
+				cs.Register(`git symbolic-ref --quiet HEAD`, 0, "refs/heads/head-ref-name\n")
+				cs.Register(`git status --porcelain`, 0, "")
+				cs.Register(`git remote -v`, 0, "origin\thttps://github.com/OWNER/REPO.git (fetch)\norigin\thttps://github.com/OWNER/REPO.git (push)\n")
+				cs.Register(`git config --get-regexp`, 1, "")
+				cs.Register(`git fetch origin head-ref-name`, 0, "")
+				cs.Register(`git merge --ff-only FETCH_HEAD`, 0, "")
+			},
+			wantStderrContains: []string{"PR branch updated\n", `updated local branch "head-ref-name"`},
+		},
+		{
+			name: "rebases instead of merging when --rebase was used",
+			input: &UpdateOptions{
+				SelectorArg:  "123",
+				Rebase:       true,
+				Checkout:     true,
+				CheckoutPull: true,
+			},
+			runStubs: func(cs *run.CommandStubber) {
+				cs.Register(`git symbolic-ref --quiet HEAD`, 0, "refs/heads/head-ref-name\n")
+				cs.Register(`git status --porcelain`, 0, "")
+				cs.Register(`git remote -v`, 0, "origin\thttps://github.com/OWNER/REPO.git (fetch)\norigin\thttps://github.com/OWNER/REPO.git (push)\n")
+				cs.Register(`git config --get-regexp`, 1, "")
+				cs.Register(`git fetch origin head-ref-name`, 0, "")
+				cs.Register(`git rebase FETCH_HEAD`, 0, "")
+			},
+			wantStderrContains: []string{"PR branch updated\n", `updated local branch "head-ref-name"`},
+		},
+		{
+			name: "only fetches, without fast-forwarding, when --checkout-pull was not given",
+			input: &UpdateOptions{
+				SelectorArg: "123",
+				Checkout:    true,
+			},
+			runStubs: func(cs *run.CommandStubber) {
+				cs.Register(`git symbolic-ref --quiet HEAD`, 0, "refs/heads/head-ref-name\n")
+				cs.Register(`git status --porcelain`, 0, "")
+				cs.Register(`git remote -v`, 0, "origin\thttps://github.com/OWNER/REPO.git (fetch)\norigin\thttps://github.com/OWNER/REPO.git (push)\n")
+				cs.Register(`git config --get-regexp`, 1, "")
+				cs.Register(`git fetch origin head-ref-name`, 0, "")
+			},
+			wantStderrContains: []string{"PR branch updated\n"},
+		},
+		{
+			name: "skips the checkout and warns when the working tree is on a different branch",
+			input: &UpdateOptions{
+				SelectorArg:  "123",
+				Checkout:     true,
+				CheckoutPull: true,
+			},
+			runStubs: func(cs *run.CommandStubber) {
+				cs.Register(`git symbolic-ref --quiet HEAD`, 0, "refs/heads/some-other-branch\n")
+			},
+			wantStderrContains: []string{"PR branch updated\n", `not currently on branch "head-ref-name"; skipping local checkout`},
+		},
+		{
+			name: "skips the checkout and warns when the working tree is dirty",
+			input: &UpdateOptions{
+				SelectorArg:  "123",
+				Checkout:     true,
+				CheckoutPull: true,
+			},
+			runStubs: func(cs *run.CommandStubber) {
+				cs.Register(`git symbolic-ref --quiet HEAD`, 0, "refs/heads/head-ref-name\n")
+				cs.Register(`git status --porcelain`, 0, "M some-file.go\n")
+			},
+			wantStderrContains: []string{"PR branch updated\n", "local working tree has uncommitted changes; skipping local checkout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _, _, stderr := iostreams.Test()
+			ios.SetStdoutTTY(true)
+			ios.SetStdinTTY(true)
+			ios.SetStderrTTY(true)
+
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(
+				httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+				httpmock.GraphQLQuery(`{
+					"data": {
+						"repository": {
+							"pullRequest": {
+								"baseRef": {
+									"compare": {
+										"aheadBy": 0,
+										"behindBy": 999,
+										"Status": "BEHIND"
+									}
+								}
+							}
+						}
+					}
+				}`, func(_ string, _ map[string]interface{}) {}))
+			reg.Register(
+				httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`),
+				httpmock.GraphQLMutation(`{
+					"data": {
+						"updatePullRequestBranch": {
+							"pullRequest": {}
+						}
+					}
+				}`, func(_ map[string]interface{}) {}))
+
+			cmdStubs, cmdTeardown := run.Stub()
+			defer cmdTeardown(t)
+			tt.runStubs(cmdStubs)
+
+			tt.input.Finder = shared.NewMockFinder("123", &api.PullRequest{
+				ID:                  "123",
+				Number:              123,
+				HeadRefOid:          "head-ref-oid",
+				HeadRefName:         "head-ref-name",
+				HeadRepositoryOwner: api.Owner{Login: "OWNER"},
+			}, ghrepo.New("OWNER", "REPO"))
+			tt.input.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+			tt.input.IO = ios
+			tt.input.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+			err := updateRun(tt.input)
+			assert.NoError(t, err)
+
+			for _, want := range tt.wantStderrContains {
+				assert.Contains(t, stderr.String(), want)
+			}
+		})
+	}
+}
+
+func Test_updateRun_watch(t *testing.T) {
+	defaultInput := func() *UpdateOptions {
+		return &UpdateOptions{
+			Finder: shared.NewMockFinder("123", &api.PullRequest{
+				ID:                  "123",
+				Number:              123,
+				HeadRefOid:          "head-ref-oid",
+				HeadRefName:         "head-ref-name",
+				HeadRepositoryOwner: api.Owner{Login: "head-repository-owner"},
+			}, ghrepo.New("OWNER", "REPO")),
+			SelectorArg: "123",
+			Watch:       true,
+			Context:     context.Background(),
+		}
+	}
+
+	behindResponse := `{
+		"data": {
+			"repository": {
+				"pullRequest": {
+					"baseRef": {
+						"compare": {
+							"aheadBy": 0,
+							"behindBy": 999,
+							"Status": "BEHIND"
+						}
+					}
+				}
+			}
+		}
+	}`
+	aheadResponse := `{
+		"data": {
+			"repository": {
+				"pullRequest": {
+					"baseRef": {
+						"compare": {
+							"aheadBy": 999,
+							"behindBy": 0,
+							"Status": "AHEAD"
+						}
+					}
+				}
+			}
+		}
+	}`
+	mutationResponse := `{
+		"data": {
+			"updatePullRequestBranch": {
+				"pullRequest": {}
+			}
+		}
+	}`
+
+	t.Run("updates once then stops as soon as the branch is ahead", func(t *testing.T) {
+		oldSleep := sleep
+		sleepCalls := 0
+		sleep = func(context.Context, time.Duration) error { sleepCalls++; return nil }
+		defer func() { sleep = oldSleep }()
+
+		reg := &httpmock.Registry{}
+		defer reg.Verify(t)
+
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(behindResponse, func(_ string, inputs map[string]interface{}) {
+				assert.Equal(t, float64(123), inputs["pullRequestNumber"])
+			}))
+		reg.Register(
+			httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`),
+			httpmock.GraphQLMutation(mutationResponse, func(inputs map[string]interface{}) {
+				assert.Equal(t, "MERGE", inputs["updateMethod"])
+			}))
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(aheadResponse, func(_ string, inputs map[string]interface{}) {}))
+
+		ios, _, _, stderr := iostreams.Test()
+		ios.SetStdoutTTY(true)
+		ios.SetStdinTTY(true)
+		ios.SetStderrTTY(true)
+
+		opts := defaultInput()
+		opts.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+		opts.IO = ios
+		opts.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+		err := updateRun(opts)
+		assert.NoError(t, err)
+		assert.Contains(t, stderr.String(), "PR branch updated, waiting to confirm")
+		assert.Contains(t, stderr.String(), "PR branch is up-to-date")
+		assert.Equal(t, 1, sleepCalls)
+	})
+
+	t.Run("retries after a transient compare error then succeeds", func(t *testing.T) {
+		oldSleep := sleep
+		sleep = func(context.Context, time.Duration) error { return nil }
+		defer func() { sleep = oldSleep }()
+
+		reg := &httpmock.Registry{}
+		defer reg.Verify(t)
+
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(`{
+				"data": {},
+				"errors": [{"message": "some transient error"}]
+			}`, func(_ string, inputs map[string]interface{}) {}))
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(aheadResponse, func(_ string, inputs map[string]interface{}) {}))
+
+		ios, _, _, stderr := iostreams.Test()
+		ios.SetStdoutTTY(true)
+		ios.SetStdinTTY(true)
+		ios.SetStderrTTY(true)
+
+		opts := defaultInput()
+		opts.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+		opts.IO = ios
+		opts.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+		err := updateRun(opts)
+		assert.NoError(t, err)
+		assert.Contains(t, stderr.String(), "some transient error")
+		assert.Contains(t, stderr.String(), "PR branch is up-to-date")
+	})
+
+	t.Run("gives up once max attempts is reached", func(t *testing.T) {
+		oldSleep := sleep
+		sleep = func(context.Context, time.Duration) error { return nil }
+		defer func() { sleep = oldSleep }()
+
+		reg := &httpmock.Registry{}
+		defer reg.Verify(t)
+
+		for i := 0; i < 2; i++ {
+			reg.Register(
+				httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+				httpmock.GraphQLQuery(behindResponse, func(_ string, inputs map[string]interface{}) {}))
+			reg.Register(
+				httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`),
+				httpmock.GraphQLMutation(mutationResponse, func(inputs map[string]interface{}) {}))
+		}
+
+		ios, _, _, _ := iostreams.Test()
+		ios.SetStdoutTTY(true)
+		ios.SetStdinTTY(true)
+		ios.SetStderrTTY(true)
+
+		opts := defaultInput()
+		opts.MaxAttempts = 2
+		opts.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+		opts.IO = ios
+		opts.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+		err := updateRun(opts)
+		assert.EqualError(t, err, "gave up waiting for pull request #123 to become up-to-date after 2 attempts")
+	})
+
+	t.Run("stops watching once the pull request is merged", func(t *testing.T) {
+		oldSleep := sleep
+		sleep = func(context.Context, time.Duration) error { return nil }
+		defer func() { sleep = oldSleep }()
+
+		mergedResponse := `{
+			"data": {
+				"repository": {
+					"pullRequest": {
+						"state": "MERGED",
+						"baseRef": {
+							"compare": {
+								"aheadBy": 0,
+								"behindBy": 999,
+								"Status": "BEHIND"
+							}
+						}
+					}
+				}
+			}
+		}`
+
+		reg := &httpmock.Registry{}
+		defer reg.Verify(t)
+
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(mergedResponse, func(_ string, inputs map[string]interface{}) {}))
+
+		ios, _, _, stderr := iostreams.Test()
+		ios.SetStdoutTTY(true)
+		ios.SetStdinTTY(true)
+		ios.SetStderrTTY(true)
+
+		opts := defaultInput()
+		opts.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+		opts.IO = ios
+		opts.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+		err := updateRun(opts)
+		assert.NoError(t, err)
+		assert.Contains(t, stderr.String(), "was merged")
+	})
+
+	t.Run("stops promptly when the context is canceled mid-wait", func(t *testing.T) {
+		reg := &httpmock.Registry{}
+		defer reg.Verify(t)
+
+		reg.Register(
+			httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+			httpmock.GraphQLQuery(behindResponse, func(_ string, inputs map[string]interface{}) {}))
+		reg.Register(
+			httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`),
+			httpmock.GraphQLMutation(mutationResponse, func(inputs map[string]interface{}) {}))
+
+		ios, _, _, stderr := iostreams.Test()
+		ios.SetStdoutTTY(true)
+		ios.SetStdinTTY(true)
+		ios.SetStderrTTY(true)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		opts := defaultInput()
+		opts.Context = ctx
+		opts.Interval = time.Hour
+		opts.GitClient = &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"}
+		opts.IO = ios
+		opts.HttpClient = func() (*http.Client, error) { return &http.Client{Transport: reg}, nil }
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- updateRun(opts) }()
+
+		// Give the update-branch mutation time to land before canceling, so
+		// the cancellation is observed during the --interval sleep rather
+		// than before the loop does any work.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-errCh:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(2 * time.Second):
+			t.Fatal("updateRun did not return promptly after the context was canceled")
+		}
+		assert.Contains(t, stderr.String(), "PR branch updated, waiting to confirm")
+	})
+}
+
+// selectorFinder is a shared.PRFinder test double that resolves a fixed PR
+// per selector, used to exercise updateRun's bulk (multi-selector) path.
+type selectorFinder struct {
+	baseRepo   ghrepo.Interface
+	bySelector map[string]*api.PullRequest
+}
+
+func (f *selectorFinder) Find(opts shared.FindOptions) (*api.PullRequest, ghrepo.Interface, error) {
+	pr, ok := f.bySelector[opts.Selector]
+	if !ok {
+		return nil, nil, fmt.Errorf("no pull requests found")
+	}
+	return pr, f.baseRepo, nil
+}
+
+func behindResp() string {
+	return `{"data":{"repository":{"pullRequest":{"baseRef":{"compare":{"aheadBy":0,"behindBy":1,"Status":"BEHIND"}}}}}}`
+}
+
+func aheadResp() string {
+	return `{"data":{"repository":{"pullRequest":{"baseRef":{"compare":{"aheadBy":1,"behindBy":0,"Status":"AHEAD"}}}}}}`
+}
+
+func mutationResp() string {
+	return `{"data":{"updatePullRequestBranch":{"pullRequest":{}}}}`
+}
+
+func Test_updateRun_bulkSelectors(t *testing.T) {
+	baseRepo := ghrepo.New("OWNER", "REPO")
+	finder := &selectorFinder{
+		baseRepo: baseRepo,
+		bySelector: map[string]*api.PullRequest{
+			"12": {ID: "pr-12", Number: 12, HeadRefOid: "oid-12", HeadRefName: "branch-12", HeadRepositoryOwner: api.Owner{Login: "OWNER"}},
+			"34": {ID: "pr-34", Number: 34, HeadRefOid: "oid-34", HeadRefName: "branch-34", HeadRepositoryOwner: api.Owner{Login: "OWNER"}},
+			"56": {ID: "pr-56", Number: 56, HeadRefOid: "oid-56", HeadRefName: "branch-56", HeadRepositoryOwner: api.Owner{Login: "OWNER"}},
+		},
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(behindResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(aheadResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(behindResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`), httpmock.GraphQLMutation(mutationResp(), func(inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`), httpmock.GraphQLMutation(mutationResp(), func(inputs map[string]interface{}) {}))
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	ios.SetStdinTTY(true)
+	ios.SetStderrTTY(true)
+
+	opts := &UpdateOptions{
+		Finder:      finder,
+		Selectors:   []string{"12", "34", "56"},
+		SelectorArg: "12",
+		Concurrency: 1,
+		GitClient:   &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"},
+		IO:          ios,
+		HttpClient:  func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+	}
+
+	err := updateRun(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "#12 updated")
+	assert.Contains(t, stderr.String(), "#34 already up-to-date")
+	assert.Contains(t, stderr.String(), "#56 updated")
+}
+
+func Test_updateRun_bulkOneFails(t *testing.T) {
+	baseRepo := ghrepo.New("OWNER", "REPO")
+	finder := &selectorFinder{
+		baseRepo: baseRepo,
+		bySelector: map[string]*api.PullRequest{
+			"12": {ID: "pr-12", Number: 12, HeadRefOid: "oid-12", HeadRefName: "branch-12", HeadRepositoryOwner: api.Owner{Login: "OWNER"}},
+			"34": {ID: "pr-34", Number: 34, HeadRefOid: "oid-34", HeadRefName: "branch-34", HeadRepositoryOwner: api.Owner{Login: "OWNER"}},
+		},
+	}
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`),
+		httpmock.GraphQLQuery(`{"data":{},"errors":[{"message":"some error"}]}`, func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(behindResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`), httpmock.GraphQLMutation(mutationResp(), func(inputs map[string]interface{}) {}))
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	ios.SetStdinTTY(true)
+	ios.SetStderrTTY(true)
+
+	opts := &UpdateOptions{
+		Finder:      finder,
+		Selectors:   []string{"12", "34"},
+		SelectorArg: "12",
+		Concurrency: 1,
+		GitClient:   &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"},
+		IO:          ios,
+		HttpClient:  func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+	}
+
+	err := updateRun(opts)
+	assert.EqualError(t, err, "1 of 2 pull requests failed to update")
+	assert.Contains(t, stderr.String(), "#12")
+	assert.Contains(t, stderr.String(), "some error")
+	assert.Contains(t, stderr.String(), "#34 updated")
+}
+
+func Test_updateRun_search(t *testing.T) {
+	baseRepo := ghrepo.New("OWNER", "REPO")
+
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestSearch\b`),
+		httpmock.GraphQLQuery(`{
+			"data": {
+				"search": {
+					"nodes": [
+						{"id": "pr-12", "number": 12, "headRefOid": "oid-12", "headRefName": "branch-12", "headRepositoryOwner": {"login": "OWNER"}},
+						{"id": "pr-34", "number": 34, "headRefOid": "oid-34", "headRefName": "branch-34", "headRepositoryOwner": {"login": "OWNER"}}
+					]
+				}
+			}
+		}`, func(_ string, inputs map[string]interface{}) {
+			assert.Equal(t, "repo:OWNER/REPO is:open author:@me", inputs["q"])
+		}))
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(behindResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`query ComparePullRequestBaseBranchWith\b`), httpmock.GraphQLQuery(aheadResp(), func(_ string, inputs map[string]interface{}) {}))
+	reg.Register(httpmock.GraphQL(`mutation PullRequestUpdateBranch\b`), httpmock.GraphQLMutation(mutationResp(), func(inputs map[string]interface{}) {}))
+
+	ios, _, _, stderr := iostreams.Test()
+	ios.SetStdoutTTY(true)
+	ios.SetStdinTTY(true)
+	ios.SetStderrTTY(true)
+
+	opts := &UpdateOptions{
+		AllAuthored: true,
+		Concurrency: 1,
+		BaseRepo:    func() (ghrepo.Interface, error) { return baseRepo, nil },
+		GitClient:   &git.Client{GhPath: "some/path/gh", GitPath: "some/path/git"},
+		IO:          ios,
+		HttpClient:  func() (*http.Client, error) { return &http.Client{Transport: reg}, nil },
+	}
+
+	err := updateRun(opts)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "#12 updated")
+	assert.Contains(t, stderr.String(), "#34 already up-to-date")
+}
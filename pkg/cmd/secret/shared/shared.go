@@ -0,0 +1,108 @@
+package shared
+
+import "strings"
+
+// SecretEntity is the level at which a secret is scoped.
+type SecretEntity int
+
+const (
+	Repository SecretEntity = iota
+	Organization
+	Environment
+	User
+)
+
+// App is the GitHub product a secret belongs to.
+type App int
+
+const (
+	Actions App = iota
+	Codespaces
+	Dependabot
+)
+
+// Unknown is the untyped sentinel returned in place of either an App or a
+// SecretEntity when the caller-provided value doesn't match a known one.
+const Unknown = -1
+
+// GetSecretEntity determines the entity to set a secret for, based on the
+// option flags provided by the user. Organization takes precedence over
+// environment, which takes precedence over user secrets; if none apply, the
+// secret is scoped to the current repository.
+func GetSecretEntity(orgName, envName string, userSecrets bool) SecretEntity {
+	switch {
+	case orgName != "":
+		return Organization
+	case envName != "":
+		return Environment
+	case userSecrets:
+		return User
+	default:
+		return Repository
+	}
+}
+
+// GetSecretApp resolves the app string passed via --app to an App,
+// defaulting based on the target entity when no app is specified.
+func GetSecretApp(app string, entity SecretEntity) App {
+	switch strings.ToLower(app) {
+	case "actions":
+		return Actions
+	case "codespaces":
+		return Codespaces
+	case "dependabot":
+		return Dependabot
+	case "":
+		if entity == User {
+			return Codespaces
+		}
+		return Actions
+	default:
+		return Unknown
+	}
+}
+
+// SecretPayloadOptions carries the values needed to build the JSON body for
+// a secret set request, regardless of which app or entity it targets.
+type SecretPayloadOptions struct {
+	EncryptedValue string
+	KeyID          string
+	// Visibility is "all", "private", or "selected"; only meaningful for
+	// secrets scoped to an Organization or a Codespaces-at-org entity.
+	Visibility            string
+	SelectedRepositoryIDs []int64
+}
+
+// BuildSecretPayload assembles the request body for creating or updating a
+// secret. Visibility is only included when set, and SelectedRepositoryIDs
+// only when Visibility is "selected" and the list is non-empty, so the same
+// payload shape can be reused across Actions, Codespaces, and
+// Dependabot secrets at any supported entity scope.
+func BuildSecretPayload(opts SecretPayloadOptions) map[string]interface{} {
+	payload := map[string]interface{}{
+		"encrypted_value": opts.EncryptedValue,
+		"key_id":          opts.KeyID,
+	}
+	if opts.Visibility != "" {
+		payload["visibility"] = opts.Visibility
+	}
+	if opts.Visibility == "selected" && len(opts.SelectedRepositoryIDs) > 0 {
+		payload["selected_repository_ids"] = opts.SelectedRepositoryIDs
+	}
+	return payload
+}
+
+// IsSupportedSecretEntity reports whether app supports storing a secret at
+// the given entity scope.
+func IsSupportedSecretEntity(app App, entity SecretEntity) bool {
+	switch app {
+	case Actions:
+		return entity == Repository || entity == Organization || entity == Environment
+	case Codespaces:
+		return entity == User || entity == Organization || entity == Repository
+	case Dependabot:
+		return entity == Repository || entity == Organization || entity == Environment
+	default:
+		return false
+	}
+}
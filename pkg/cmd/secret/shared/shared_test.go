@@ -139,10 +139,10 @@ func TestIsSupportedSecretEntity(t *testing.T) {
 			app:  Codespaces,
 			supportedEntities: []SecretEntity{
 				User,
+				Organization,
+				Repository,
 			},
 			unsupportedEntities: []SecretEntity{
-				Repository,
-				Organization,
 				Environment,
 				Unknown,
 			},
@@ -153,9 +153,9 @@ func TestIsSupportedSecretEntity(t *testing.T) {
 			supportedEntities: []SecretEntity{
 				Repository,
 				Organization,
+				Environment,
 			},
 			unsupportedEntities: []SecretEntity{
-				Environment,
 				User,
 				Unknown,
 			},
@@ -174,3 +174,33 @@ func TestIsSupportedSecretEntity(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSecretPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SecretPayloadOptions
+		want map[string]interface{}
+	}{
+		{
+			name: "repository secret omits visibility",
+			opts: SecretPayloadOptions{EncryptedValue: "enc", KeyID: "key"},
+			want: map[string]interface{}{"encrypted_value": "enc", "key_id": "key"},
+		},
+		{
+			name: "org secret visible to all",
+			opts: SecretPayloadOptions{EncryptedValue: "enc", KeyID: "key", Visibility: "all"},
+			want: map[string]interface{}{"encrypted_value": "enc", "key_id": "key", "visibility": "all"},
+		},
+		{
+			name: "org secret selected repos",
+			opts: SecretPayloadOptions{EncryptedValue: "enc", KeyID: "key", Visibility: "selected", SelectedRepositoryIDs: []int64{1, 2}},
+			want: map[string]interface{}{"encrypted_value": "enc", "key_id": "key", "visibility": "selected", "selected_repository_ids": []int64{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, BuildSecretPayload(tt.opts))
+		})
+	}
+}